@@ -2,7 +2,7 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v3.6.1
-// source: dns.proto
+// source: api/v1/dns.proto
 
 package dns
 
@@ -19,9 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DnsService_AddEntry_FullMethodName    = "/l2smdns.DnsService/AddEntry"
-	DnsService_AddServer_FullMethodName   = "/l2smdns.DnsService/AddServer"
-	DnsService_DeleteEntry_FullMethodName = "/l2smdns.DnsService/DeleteEntry"
+	DnsService_AddEntry_FullMethodName           = "/l2smdns.DnsService/AddEntry"
+	DnsService_AddServer_FullMethodName          = "/l2smdns.DnsService/AddServer"
+	DnsService_RemoveServer_FullMethodName       = "/l2smdns.DnsService/RemoveServer"
+	DnsService_ListServers_FullMethodName        = "/l2smdns.DnsService/ListServers"
+	DnsService_DeleteEntry_FullMethodName        = "/l2smdns.DnsService/DeleteEntry"
+	DnsService_RenewEntry_FullMethodName         = "/l2smdns.DnsService/RenewEntry"
+	DnsService_ListEntries_FullMethodName        = "/l2smdns.DnsService/ListEntries"
+	DnsService_BatchAddEntries_FullMethodName    = "/l2smdns.DnsService/BatchAddEntries"
+	DnsService_BatchDeleteEntries_FullMethodName = "/l2smdns.DnsService/BatchDeleteEntries"
+	DnsService_WatchEntries_FullMethodName       = "/l2smdns.DnsService/WatchEntries"
+	DnsService_AddRecord_FullMethodName          = "/l2smdns.DnsService/AddRecord"
+	DnsService_DeleteRecord_FullMethodName       = "/l2smdns.DnsService/DeleteRecord"
+	DnsService_ListRecords_FullMethodName        = "/l2smdns.DnsService/ListRecords"
 )
 
 // DnsServiceClient is the client API for DnsService service.
@@ -30,7 +40,17 @@ const (
 type DnsServiceClient interface {
 	AddEntry(ctx context.Context, in *AddEntryRequest, opts ...grpc.CallOption) (*AddEntryResponse, error)
 	AddServer(ctx context.Context, in *AddServerRequest, opts ...grpc.CallOption) (*AddServerResponse, error)
+	RemoveServer(ctx context.Context, in *RemoveServerRequest, opts ...grpc.CallOption) (*RemoveServerResponse, error)
+	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
 	DeleteEntry(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*DeleteEntryResponse, error)
+	RenewEntry(ctx context.Context, in *RenewEntryRequest, opts ...grpc.CallOption) (*RenewEntryResponse, error)
+	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
+	BatchAddEntries(ctx context.Context, in *BatchAddEntriesRequest, opts ...grpc.CallOption) (*BatchAddEntriesResponse, error)
+	BatchDeleteEntries(ctx context.Context, in *BatchDeleteEntriesRequest, opts ...grpc.CallOption) (*BatchDeleteEntriesResponse, error)
+	WatchEntries(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEntriesResponse], error)
+	AddRecord(ctx context.Context, in *AddRecordRequest, opts ...grpc.CallOption) (*AddRecordResponse, error)
+	DeleteRecord(ctx context.Context, in *DeleteRecordRequest, opts ...grpc.CallOption) (*DeleteRecordResponse, error)
+	ListRecords(ctx context.Context, in *ListRecordsRequest, opts ...grpc.CallOption) (*ListRecordsResponse, error)
 }
 
 type dnsServiceClient struct {
@@ -61,6 +81,26 @@ func (c *dnsServiceClient) AddServer(ctx context.Context, in *AddServerRequest,
 	return out, nil
 }
 
+func (c *dnsServiceClient) RemoveServer(ctx context.Context, in *RemoveServerRequest, opts ...grpc.CallOption) (*RemoveServerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveServerResponse)
+	err := c.cc.Invoke(ctx, DnsService_RemoveServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServersResponse)
+	err := c.cc.Invoke(ctx, DnsService_ListServers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dnsServiceClient) DeleteEntry(ctx context.Context, in *DeleteEntryRequest, opts ...grpc.CallOption) (*DeleteEntryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteEntryResponse)
@@ -71,13 +111,112 @@ func (c *dnsServiceClient) DeleteEntry(ctx context.Context, in *DeleteEntryReque
 	return out, nil
 }
 
+func (c *dnsServiceClient) RenewEntry(ctx context.Context, in *RenewEntryRequest, opts ...grpc.CallOption) (*RenewEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenewEntryResponse)
+	err := c.cc.Invoke(ctx, DnsService_RenewEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEntriesResponse)
+	err := c.cc.Invoke(ctx, DnsService_ListEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) BatchAddEntries(ctx context.Context, in *BatchAddEntriesRequest, opts ...grpc.CallOption) (*BatchAddEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAddEntriesResponse)
+	err := c.cc.Invoke(ctx, DnsService_BatchAddEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) BatchDeleteEntries(ctx context.Context, in *BatchDeleteEntriesRequest, opts ...grpc.CallOption) (*BatchDeleteEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchDeleteEntriesResponse)
+	err := c.cc.Invoke(ctx, DnsService_BatchDeleteEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) WatchEntries(ctx context.Context, in *WatchEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEntriesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DnsService_ServiceDesc.Streams[0], DnsService_WatchEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEntriesRequest, WatchEntriesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DnsService_WatchEntriesClient = grpc.ServerStreamingClient[WatchEntriesResponse]
+
+func (c *dnsServiceClient) AddRecord(ctx context.Context, in *AddRecordRequest, opts ...grpc.CallOption) (*AddRecordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddRecordResponse)
+	err := c.cc.Invoke(ctx, DnsService_AddRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) DeleteRecord(ctx context.Context, in *DeleteRecordRequest, opts ...grpc.CallOption) (*DeleteRecordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRecordResponse)
+	err := c.cc.Invoke(ctx, DnsService_DeleteRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dnsServiceClient) ListRecords(ctx context.Context, in *ListRecordsRequest, opts ...grpc.CallOption) (*ListRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecordsResponse)
+	err := c.cc.Invoke(ctx, DnsService_ListRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DnsServiceServer is the server API for DnsService service.
 // All implementations must embed UnimplementedDnsServiceServer
 // for forward compatibility.
 type DnsServiceServer interface {
 	AddEntry(context.Context, *AddEntryRequest) (*AddEntryResponse, error)
 	AddServer(context.Context, *AddServerRequest) (*AddServerResponse, error)
+	RemoveServer(context.Context, *RemoveServerRequest) (*RemoveServerResponse, error)
+	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
 	DeleteEntry(context.Context, *DeleteEntryRequest) (*DeleteEntryResponse, error)
+	RenewEntry(context.Context, *RenewEntryRequest) (*RenewEntryResponse, error)
+	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
+	BatchAddEntries(context.Context, *BatchAddEntriesRequest) (*BatchAddEntriesResponse, error)
+	BatchDeleteEntries(context.Context, *BatchDeleteEntriesRequest) (*BatchDeleteEntriesResponse, error)
+	WatchEntries(*WatchEntriesRequest, grpc.ServerStreamingServer[WatchEntriesResponse]) error
+	AddRecord(context.Context, *AddRecordRequest) (*AddRecordResponse, error)
+	DeleteRecord(context.Context, *DeleteRecordRequest) (*DeleteRecordResponse, error)
+	ListRecords(context.Context, *ListRecordsRequest) (*ListRecordsResponse, error)
 	mustEmbedUnimplementedDnsServiceServer()
 }
 
@@ -94,9 +233,39 @@ func (UnimplementedDnsServiceServer) AddEntry(context.Context, *AddEntryRequest)
 func (UnimplementedDnsServiceServer) AddServer(context.Context, *AddServerRequest) (*AddServerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddServer not implemented")
 }
+func (UnimplementedDnsServiceServer) RemoveServer(context.Context, *RemoveServerRequest) (*RemoveServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveServer not implemented")
+}
+func (UnimplementedDnsServiceServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
 func (UnimplementedDnsServiceServer) DeleteEntry(context.Context, *DeleteEntryRequest) (*DeleteEntryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntry not implemented")
 }
+func (UnimplementedDnsServiceServer) RenewEntry(context.Context, *RenewEntryRequest) (*RenewEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewEntry not implemented")
+}
+func (UnimplementedDnsServiceServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEntries not implemented")
+}
+func (UnimplementedDnsServiceServer) BatchAddEntries(context.Context, *BatchAddEntriesRequest) (*BatchAddEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAddEntries not implemented")
+}
+func (UnimplementedDnsServiceServer) BatchDeleteEntries(context.Context, *BatchDeleteEntriesRequest) (*BatchDeleteEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEntries not implemented")
+}
+func (UnimplementedDnsServiceServer) WatchEntries(*WatchEntriesRequest, grpc.ServerStreamingServer[WatchEntriesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEntries not implemented")
+}
+func (UnimplementedDnsServiceServer) AddRecord(context.Context, *AddRecordRequest) (*AddRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRecord not implemented")
+}
+func (UnimplementedDnsServiceServer) DeleteRecord(context.Context, *DeleteRecordRequest) (*DeleteRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecord not implemented")
+}
+func (UnimplementedDnsServiceServer) ListRecords(context.Context, *ListRecordsRequest) (*ListRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecords not implemented")
+}
 func (UnimplementedDnsServiceServer) mustEmbedUnimplementedDnsServiceServer() {}
 func (UnimplementedDnsServiceServer) testEmbeddedByValue()                    {}
 
@@ -154,6 +323,42 @@ func _DnsService_AddServer_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DnsService_RemoveServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).RemoveServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_RemoveServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).RemoveServer(ctx, req.(*RemoveServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_ListServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).ListServers(ctx, req.(*ListServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DnsService_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteEntryRequest)
 	if err := dec(in); err != nil {
@@ -172,6 +377,143 @@ func _DnsService_DeleteEntry_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DnsService_RenewEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).RenewEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_RenewEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).RenewEntry(ctx, req.(*RenewEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).ListEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_ListEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).ListEntries(ctx, req.(*ListEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_BatchAddEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAddEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).BatchAddEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_BatchAddEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).BatchAddEntries(ctx, req.(*BatchAddEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_BatchDeleteEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).BatchDeleteEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_BatchDeleteEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).BatchDeleteEntries(ctx, req.(*BatchDeleteEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_WatchEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEntriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DnsServiceServer).WatchEntries(m, &grpc.GenericServerStream[WatchEntriesRequest, WatchEntriesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DnsService_WatchEntriesServer = grpc.ServerStreamingServer[WatchEntriesResponse]
+
+func _DnsService_AddRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).AddRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_AddRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).AddRecord(ctx, req.(*AddRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_DeleteRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).DeleteRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_DeleteRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).DeleteRecord(ctx, req.(*DeleteRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DnsService_ListRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DnsServiceServer).ListRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DnsService_ListRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DnsServiceServer).ListRecords(ctx, req.(*ListRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DnsService_ServiceDesc is the grpc.ServiceDesc for DnsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,11 +529,53 @@ var DnsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddServer",
 			Handler:    _DnsService_AddServer_Handler,
 		},
+		{
+			MethodName: "RemoveServer",
+			Handler:    _DnsService_RemoveServer_Handler,
+		},
+		{
+			MethodName: "ListServers",
+			Handler:    _DnsService_ListServers_Handler,
+		},
 		{
 			MethodName: "DeleteEntry",
 			Handler:    _DnsService_DeleteEntry_Handler,
 		},
+		{
+			MethodName: "RenewEntry",
+			Handler:    _DnsService_RenewEntry_Handler,
+		},
+		{
+			MethodName: "ListEntries",
+			Handler:    _DnsService_ListEntries_Handler,
+		},
+		{
+			MethodName: "BatchAddEntries",
+			Handler:    _DnsService_BatchAddEntries_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEntries",
+			Handler:    _DnsService_BatchDeleteEntries_Handler,
+		},
+		{
+			MethodName: "AddRecord",
+			Handler:    _DnsService_AddRecord_Handler,
+		},
+		{
+			MethodName: "DeleteRecord",
+			Handler:    _DnsService_DeleteRecord_Handler,
+		},
+		{
+			MethodName: "ListRecords",
+			Handler:    _DnsService_ListRecords_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEntries",
+			Handler:       _DnsService_WatchEntries_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "dns.proto",
+	Metadata: "api/v1/dns.proto",
 }
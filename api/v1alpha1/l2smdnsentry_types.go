@@ -0,0 +1,64 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// L2SMDNSEntrySpec defines the DNS name to publish and the addresses it resolves to.
+type L2SMDNSEntrySpec struct {
+	// Hostname is the fully qualified DNS name, e.g. my-pod.my-net.global.l2sm.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Hostname string `json:"hostname"`
+
+	// IPAddresses are the IPv4 and IPv6 addresses Hostname resolves to.
+	// +kubebuilder:validation:MinItems=1
+	IPAddresses []string `json:"ipAddresses"`
+
+	// ExpiresAt is when the entry lapses, unless it is renewed. Entries without it never expire.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=l2smdns
+// +kubebuilder:printcolumn:name="Hostname",type=string,JSONPath=`.spec.hostname`
+// +kubebuilder:printcolumn:name="Addresses",type=string,JSONPath=`.spec.ipAddresses`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// L2SMDNSEntry is a DNS entry served by L2SM DNS. When the CRD store is enabled, the entries of
+// the Corefile are rendered from the set of L2SMDNSEntry resources.
+type L2SMDNSEntry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec L2SMDNSEntrySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// L2SMDNSEntryList contains a list of L2SMDNSEntry.
+type L2SMDNSEntryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []L2SMDNSEntry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&L2SMDNSEntry{}, &L2SMDNSEntryList{})
+}
@@ -15,51 +15,214 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"path/filepath"
 
 	"github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1alpha1"
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/controller"
 	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
 	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/propagation"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/responder"
 	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
 func main() {
+	// Fail early if a variable is malformed, instead of running with its default.
+	if err := env.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", env.GetServerPort()))
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// Fail early if the configured naming template is invalid.
+	if _, err := configmapmanager.DefaultNameTemplate(); err != nil {
+		log.Fatalf("Invalid DNS naming configuration: %v", err)
+	}
+
 	// Create a new gRPC server.
 	grpcServer := grpc.NewServer()
 
-	// Attempt to get an in-cluster config; if not available, fallback to kubeconfig.
-	k8sConfig, err := rest.InClusterConfig()
-	if err != nil {
-		k8sConfig, err = clientcmd.BuildConfigFromFlags("", filepath.Join(homedir.HomeDir(), ".kube", "config"))
-		if err != nil {
-			log.Fatalf("could not create config from either in-cluster or kubeconfig: %v", err)
-		}
-	}
-
 	// Read namespace and configmap name from environment variables.
 	// Defaults: "default" and "l2smdns-coredns-config".
 	namespace := env.GetConfigMapNS()
 	configmapName := env.GetConfigMapName()
 
-	// Create a new configmapmanager using the provided namespace and configmap name.
-	dnsManager, err := configmapmanager.NewDNSManager(namespace, configmapName, k8sConfig, nil)
-	if err != nil {
-		log.Fatalf("Failed to create CoreDNS Manager: %v", err)
+	var k8sConfig *rest.Config
+	var dnsManager configmapmanager.DNSManager
+	switch backend := env.GetStorageBackend(); backend {
+	case "kubernetes":
+		// Attempt to get an in-cluster config; if not available, fallback to kubeconfig.
+		k8sConfig, err = rest.InClusterConfig()
+		if err != nil {
+			k8sConfig, err = clientcmd.BuildConfigFromFlags("", filepath.Join(homedir.HomeDir(), ".kube", "config"))
+			if err != nil {
+				log.Fatalf("could not create config from either in-cluster or kubeconfig: %v", err)
+			}
+		}
+
+		// Create a new configmapmanager using the provided namespace and configmap name.
+		dnsManager, err = configmapmanager.NewDNSManager(namespace, configmapName, k8sConfig, nil)
+		if err != nil {
+			log.Fatalf("Failed to create CoreDNS Manager: %v", err)
+		}
+
+		// CoreDNS only loads the shards projected into its volume, which the manifest lists one by one.
+		if env.GetRecordStorage() == configmapmanager.StorageSharded && env.GetPodName() != "" {
+			checkShardVolumes(k8sConfig, configmapName)
+		}
+	case "file":
+		// Keep the Corefile on local disk, for a standalone CoreDNS. The features that watch
+		// Kubernetes resources are not available.
+		if env.GetPodControllerEnabled() || env.GetGCInterval() > 0 || env.GetCRDStoreEnabled() {
+			log.Fatalf("The pod controller, the sweeper and the L2SMDNSEntry store need DNS_STORAGE_BACKEND=kubernetes")
+		}
+		dnsManager, err = configmapmanager.NewFileDNSManager(env.GetCoreDNSConfigDir(), configmapName)
+		if err != nil {
+			log.Fatalf("Failed to create CoreDNS Manager: %v", err)
+		}
+		log.Printf("Keeping the CoreDNS configuration in %s", env.GetCoreDNSConfigDir())
+	default:
+		log.Fatalf("Invalid DNS_STORAGE_BACKEND %q: must be kubernetes or file", backend)
+	}
+
+	// Coalesce entry mutations into fewer ConfigMap updates if a batch window is configured.
+	if window := env.GetBatchWindow(); window > 0 {
+		dnsManager = configmapmanager.NewBatchingDNSManager(dnsManager, window, env.GetBatchMaxSize())
+		log.Printf("Batching DNS entry updates every %v (max %d per batch)", window, env.GetBatchMaxSize())
+	}
+
+	// Optionally keep the entries as L2SMDNSEntry resources, which the entry controller renders into
+	// the Corefile through the ConfigMap-backed manager.
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to set up scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to set up scheme: %v", err)
+	}
+	configMapManager := dnsManager
+	if env.GetCRDStoreEnabled() {
+		crClient, err := client.New(k8sConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			log.Fatalf("Failed to create Kubernetes client: %v", err)
+		}
+		dnsManager, err = configmapmanager.NewCRDDNSManager(configMapManager, crClient, namespace, nil)
+		if err != nil {
+			log.Fatalf("Failed to create L2SMDNSEntry store: %v", err)
+		}
+		log.Printf("Storing DNS entries as L2SMDNSEntry resources")
+	}
+
+	// Lay the existing records out according to DNS_RECORD_STORAGE, instead of waiting for the next
+	// write, and import them as L2SMDNSEntry resources when the store is first enabled.
+	if err := dnsManager.MigrateRecordStorage(context.Background()); err != nil {
+		log.Fatalf("Failed to migrate DNS record storage: %v", err)
+	}
+
+	// Remove the entries whose lease has lapsed.
+	if interval := env.GetLeaseCheckInterval(); interval > 0 {
+		go configmapmanager.ExpireLeasesEvery(context.Background(), dnsManager, interval)
+	}
+
+	// Optionally run the entry controller, the pod controller, which registers the L2SM network
+	// attachments of pods automatically, and the sweeper, which removes the entries of pods that no
+	// longer exist.
+	if env.GetPodControllerEnabled() || env.GetGCInterval() > 0 || env.GetCRDStoreEnabled() {
+		ctrl.SetLogger(klog.NewKlogr())
+		mgr, err := ctrl.NewManager(k8sConfig, ctrl.Options{
+			Scheme:  scheme,
+			Metrics: metricsserver.Options{BindAddress: "0"},
+		})
+		if err != nil {
+			log.Fatalf("Failed to create controller manager: %v", err)
+		}
+		if env.GetCRDStoreEnabled() {
+			entryReconciler := &controller.EntryReconciler{
+				Client:     mgr.GetClient(),
+				DNSManager: configMapManager,
+			}
+			if err := entryReconciler.SetupWithManager(mgr); err != nil {
+				log.Fatalf("Failed to set up entry controller: %v", err)
+			}
+		}
+		if env.GetPodControllerEnabled() {
+			podReconciler := &controller.PodReconciler{
+				Client:     mgr.GetClient(),
+				DNSManager: dnsManager,
+				Scope:      env.GetPodControllerScope(),
+				Cluster:    env.GetClusterName(),
+			}
+			if err := podReconciler.SetupWithManager(mgr); err != nil {
+				log.Fatalf("Failed to set up pod controller: %v", err)
+			}
+			log.Printf("Pod controller enabled for scope %q", env.GetPodControllerScope())
+		}
+		if interval := env.GetGCInterval(); interval > 0 {
+			sweeper := &controller.Sweeper{
+				Client:      mgr.GetClient(),
+				DNSManager:  dnsManager,
+				Interval:    interval,
+				GracePeriod: env.GetGCGracePeriod(),
+				DryRun:      env.GetGCDryRun(),
+				Cluster:     env.GetClusterName(),
+			}
+			if err := mgr.Add(sweeper); err != nil {
+				log.Fatalf("Failed to set up sweeper: %v", err)
+			}
+			log.Printf("Sweeping stale DNS entries every %v (grace period %v, dry run %t)", interval, sweeper.GracePeriod, sweeper.DryRun)
+		}
+		go func() {
+			if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+				log.Fatalf("Controller manager stopped: %v", err)
+			}
+		}()
+	}
+
+	// Optionally answer the queries for the L2SM zone directly, so that changes resolve without
+	// waiting for CoreDNS to reload the ConfigMap.
+	if env.GetResponderEnabled() {
+		dnsResponder := &responder.Responder{
+			DNSManager:      dnsManager,
+			Addr:            env.GetResponderAddr(),
+			TLD:             env.GetNameTLD(),
+			Upstreams:       env.GetResponderUpstreams(),
+			RefreshInterval: env.GetResponderRefreshInterval(),
+		}
+		go func() {
+			if err := dnsResponder.Start(context.Background()); err != nil {
+				log.Fatalf("DNS responder stopped: %v", err)
+			}
+		}()
+		log.Printf("Serving the %s zone on %s", env.GetNameTLD(), env.GetResponderAddr())
 	}
 
 	// Register the DNS service server.
-	dns.RegisterDnsServiceServer(grpcServer, &server{dns.UnimplementedDnsServiceServer{}, dnsManager})
+	dns.RegisterDnsServiceServer(grpcServer, &server{
+		DNSManager: dnsManager,
+		waiter: &propagation.Waiter{
+			Endpoint: env.GetPropagationEndpoint(),
+			Interval: env.GetPropagationInterval(),
+		},
+	})
 
 	log.Printf("Server listening at %v", lis.Addr())
 
@@ -68,3 +231,22 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// checkShardVolumes exits if the pod of the server mounts the ConfigMap without all its record shards.
+func checkShardVolumes(k8sConfig *rest.Config, configmapName string) {
+	clientset, err := kubernetes.NewForConfig(k8sConfig)
+	if err != nil {
+		log.Fatalf("Failed to create Kubernetes clientset: %v", err)
+	}
+	pod, err := clientset.CoreV1().Pods(env.GetPodNamespace()).Get(context.Background(), env.GetPodName(), metav1.GetOptions{})
+	if err != nil {
+		log.Fatalf("Failed to read pod %s to check the record shard volumes: %v", env.GetPodName(), err)
+	}
+	mounted, err := configmapmanager.CheckShardVolumes(pod, configmapName)
+	if err != nil {
+		log.Fatalf("Invalid record shard volumes: %v", err)
+	}
+	if !mounted {
+		log.Printf("No volume of pod %s mounts ConfigMap %s: CoreDNS must mount its %d record shards", pod.Name, configmapName, env.GetRecordShards())
+	}
+}
@@ -16,65 +16,627 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
 	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/propagation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type server struct {
 	dns.UnimplementedDnsServiceServer
 	configmapmanager.DNSManager
+	// waiter waits for the entries added with a wait_timeout to resolve.
+	waiter *propagation.Waiter
 }
 
 // CreateNetwork calls a method from mdclient to create a network
 func (s *server) AddEntry(ctx context.Context, req *dns.AddEntryRequest) (*dns.AddEntryResponse, error) {
 
-	dnsEntry := configmapmanager.DNSEntry{PodName: req.Entry.GetPodName(), Network: req.Entry.GetNetwork(), Scope: req.Entry.GetScope()}
-
-	entryKey, err := configmapmanager.GenerateKey(dnsEntry)
+	entryKey, err := configmapmanager.GenerateKey(toDNSEntry(req.GetEntry()))
 
 	if err != nil {
-		return &dns.AddEntryResponse{}, fmt.Errorf("could not generate entry key. err: %v", err)
+		return &dns.AddEntryResponse{}, statusError(err, "could not generate entry key", "entry")
+	}
+
+	if req.GetWaitTimeout() != nil && req.GetWaitTimeout().AsDuration() <= 0 {
+		err := fmt.Errorf("must be positive, got %v", req.GetWaitTimeout().AsDuration())
+		return &dns.AddEntryResponse{}, statusError(&configmapmanager.Error{Kind: configmapmanager.ErrInvalidArgument, Field: "wait_timeout", Err: err}, "invalid wait timeout", "")
 	}
 
-	err = s.DNSManager.AddDNSEntry(context.TODO(), entryKey, req.GetEntry().GetIpAddress())
+	if req.GetTtl() != nil && req.GetTtl().AsDuration() < 0 {
+		err := fmt.Errorf("must not be negative, got %v", req.GetTtl().AsDuration())
+		return &dns.AddEntryResponse{}, statusError(&configmapmanager.Error{Kind: configmapmanager.ErrInvalidArgument, Field: "ttl", Err: err}, "invalid ttl", "")
+	}
+
+	change := configmapmanager.EntryChange{Op: configmapmanager.EntryAdd, DNSName: entryKey, IPAddresses: entryAddresses(req.GetEntry())}
+	if req.GetTtl() != nil {
+		change.Op, change.TTL = configmapmanager.EntryAddWithLease, req.GetTtl().AsDuration()
+	}
+	result, err := s.applyEntryChange(ctx, change)
 
 	if err != nil {
-		return &dns.AddEntryResponse{}, fmt.Errorf("could not create entry. err: %v", err)
+		return &dns.AddEntryResponse{}, statusError(err, "could not create entry", "entry")
 	}
 
-	return &dns.AddEntryResponse{}, nil
+	resp := &dns.AddEntryResponse{Fqdn: entryKey, IpAddresses: result.IPAddresses, Changed: result.Changed, ResourceVersion: result.ResourceVersion}
+	if resp.Changed {
+		resp.Message = fmt.Sprintf("registered %s on %s", entryKey, strings.Join(resp.IpAddresses, ", "))
+	} else {
+		resp.Message = fmt.Sprintf("%s was already registered on %s", entryKey, strings.Join(resp.IpAddresses, ", "))
+	}
+
+	if req.GetWaitTimeout() != nil {
+		waitCtx, cancel := context.WithTimeout(ctx, req.GetWaitTimeout().AsDuration())
+		defer cancel()
+		err = s.waiter.WaitForAddresses(waitCtx, entryKey, entryAddresses(req.GetEntry())...)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &dns.AddEntryResponse{}, status.Errorf(codes.DeadlineExceeded, "entry was added but did not propagate. err: %v", err)
+		}
+		if err != nil {
+			return &dns.AddEntryResponse{}, statusError(err, "entry was added but could not wait for it to propagate", "entry")
+		}
+	}
+
+	return resp, nil
 
 }
 func (s *server) DeleteEntry(ctx context.Context, req *dns.DeleteEntryRequest) (*dns.DeleteEntryResponse, error) {
 
-	dnsEntry := configmapmanager.DNSEntry{PodName: req.Entry.GetPodName(), Network: req.Entry.GetNetwork(), Scope: req.Entry.GetScope()}
+	entryKey, err := configmapmanager.GenerateKey(toDNSEntry(req.GetEntry()))
+
+	if err != nil {
+		return &dns.DeleteEntryResponse{}, statusError(err, "could not generate entry key", "entry")
+	}
+
+	// Without addresses, the entry is removed from every address it is registered on.
+	result, err := s.applyEntryChange(ctx, configmapmanager.EntryChange{Op: configmapmanager.EntryRemove, DNSName: entryKey, IPAddresses: entryAddresses(req.GetEntry())})
+
+	if err != nil {
+		return &dns.DeleteEntryResponse{}, statusError(err, "could not delete entry", "entry")
+	}
+
+	resp := &dns.DeleteEntryResponse{Fqdn: entryKey, IpAddresses: result.IPAddresses, Changed: result.Changed, ResourceVersion: result.ResourceVersion}
+	switch {
+	case !resp.Changed:
+		resp.Message = fmt.Sprintf("%s was not registered on the given addresses", entryKey)
+	case len(resp.IpAddresses) > 0:
+		resp.Message = fmt.Sprintf("unregistered %s, which is still registered on %s", entryKey, strings.Join(resp.IpAddresses, ", "))
+	default:
+		resp.Message = fmt.Sprintf("unregistered %s", entryKey)
+	}
+	return resp, nil
+
+}
+
+func (s *server) RenewEntry(ctx context.Context, req *dns.RenewEntryRequest) (*dns.RenewEntryResponse, error) {
 
-	entryKey, err := configmapmanager.GenerateKey(dnsEntry)
+	entryKey, err := configmapmanager.GenerateKey(toDNSEntry(req.GetEntry()))
 
 	if err != nil {
-		return &dns.DeleteEntryResponse{}, fmt.Errorf("could not generate entry key. err: %v", err)
+		return &dns.RenewEntryResponse{}, statusError(err, "could not generate entry key", "entry")
 	}
 
-	err = s.DNSManager.RemoveDNSEntry(context.TODO(), entryKey, req.GetEntry().GetIpAddress())
+	err = s.DNSManager.RenewDNSLease(ctx, entryKey, req.GetTtl().AsDuration())
 
 	if err != nil {
-		return &dns.DeleteEntryResponse{}, fmt.Errorf("could not create entry. err: %v", err)
+		return &dns.RenewEntryResponse{}, statusError(err, "could not renew entry", "entry")
 	}
 
-	return &dns.DeleteEntryResponse{}, nil
+	return &dns.RenewEntryResponse{}, nil
 
 }
 
 func (s *server) AddServer(ctx context.Context, req *dns.AddServerRequest) (*dns.AddServerResponse, error) {
 
-	err := s.DNSManager.AddServerToConfigMap(ctx, req.Server.GetDomPort(), req.Server.GetServerDomain(), req.Server.GetServerPort())
+	result, err := s.DNSManager.AddForwardServer(ctx, req.Server.GetDomPort(), toForwardConfig(req.GetServer()))
+
+	if err != nil {
+		return &dns.AddServerResponse{}, statusError(err, "could not create server", "server")
+
+	}
+
+	resp := &dns.AddServerResponse{Server: toServer(result.Server), Changed: result.Changed, ResourceVersion: result.ResourceVersion}
+	if resp.Changed {
+		resp.Message = fmt.Sprintf("configured server %s", req.GetServer().GetDomPort())
+	} else {
+		resp.Message = fmt.Sprintf("server %s was already configured", req.GetServer().GetDomPort())
+	}
+	return resp, nil
+
+}
+
+func (s *server) RemoveServer(ctx context.Context, req *dns.RemoveServerRequest) (*dns.RemoveServerResponse, error) {
+
+	err := s.DNSManager.RemoveServerFromConfigMap(ctx, req.GetDomPort())
+
+	if err != nil {
+		return &dns.RemoveServerResponse{}, statusError(err, "could not remove server", "server")
+	}
+	return &dns.RemoveServerResponse{}, nil
+
+}
+
+func (s *server) ListServers(ctx context.Context, req *dns.ListServersRequest) (*dns.ListServersResponse, error) {
+
+	servers, err := s.DNSManager.ListServers(ctx)
+
+	if err != nil {
+		return &dns.ListServersResponse{}, statusError(err, "could not list servers", "")
+	}
+
+	resp := &dns.ListServersResponse{}
+	for _, server := range servers {
+		resp.Servers = append(resp.Servers, toServer(server))
+	}
+	return resp, nil
+
+}
+
+func (s *server) AddRecord(ctx context.Context, req *dns.AddRecordRequest) (*dns.AddRecordResponse, error) {
+
+	record, err := toRecord(req.GetRecord())
+	if err != nil {
+		return &dns.AddRecordResponse{}, statusError(err, "invalid record", "record")
+	}
+
+	err = s.DNSManager.AddRecord(ctx, record)
+
+	if err != nil {
+		return &dns.AddRecordResponse{}, statusError(err, "could not create record", "record")
+	}
+	return &dns.AddRecordResponse{}, nil
 
+}
+
+func (s *server) DeleteRecord(ctx context.Context, req *dns.DeleteRecordRequest) (*dns.DeleteRecordResponse, error) {
+
+	record, err := toRecord(req.GetRecord())
 	if err != nil {
-		return &dns.AddServerResponse{}, fmt.Errorf("could not create server. err: %v", err)
+		return &dns.DeleteRecordResponse{}, statusError(err, "invalid record", "record")
+	}
+
+	err = s.DNSManager.RemoveRecord(ctx, record)
+
+	if err != nil {
+		return &dns.DeleteRecordResponse{}, statusError(err, "could not delete record", "record")
+	}
+	return &dns.DeleteRecordResponse{}, nil
+
+}
+
+func (s *server) ListRecords(ctx context.Context, req *dns.ListRecordsRequest) (*dns.ListRecordsResponse, error) {
+
+	records, err := s.DNSManager.ListRecords(ctx)
+
+	if err != nil {
+		return &dns.ListRecordsResponse{}, statusError(err, "could not list records", "")
+	}
+
+	resp := &dns.ListRecordsResponse{}
+	name := strings.TrimSuffix(strings.ToLower(req.GetName()), ".")
+	for _, record := range records {
+		if req.GetType() != dns.RecordType_RECORD_TYPE_UNSPECIFIED && recordTypes[req.GetType()] != record.Type {
+			continue
+		}
+		if name != "" && record.Name != name {
+			continue
+		}
+		resp.Records = append(resp.Records, fromRecord(record))
+	}
+	return resp, nil
+
+}
+
+func (s *server) BatchAddEntries(ctx context.Context, req *dns.BatchAddEntriesRequest) (*dns.BatchAddEntriesResponse, error) {
+
+	results, err := s.applyBatch(ctx, req.GetEntries(), true, req.GetAllOrNothing())
+	if err != nil {
+		return &dns.BatchAddEntriesResponse{}, statusError(err, "could not create entries", "")
+	}
+
+	return &dns.BatchAddEntriesResponse{Results: results}, nil
+}
+
+func (s *server) BatchDeleteEntries(ctx context.Context, req *dns.BatchDeleteEntriesRequest) (*dns.BatchDeleteEntriesResponse, error) {
+
+	results, err := s.applyBatch(ctx, req.GetEntries(), false, req.GetAllOrNothing())
+	if err != nil {
+		return &dns.BatchDeleteEntriesResponse{}, statusError(err, "could not delete entries", "")
+	}
+
+	return &dns.BatchDeleteEntriesResponse{Results: results}, nil
+}
+
+// applyBatch validates a batch of entries and applies the valid ones, in order, with a single
+// ApplyEntryChanges call, so that the result of every entry is that of its own change: entries
+// repeated within the batch see the effect of the earlier ones. If allOrNothing is set and any entry
+// is invalid, nothing is applied and the valid entries are reported as aborted. allOrNothing is
+// refused by managers that cannot apply a batch atomically.
+func (s *server) applyBatch(ctx context.Context, entries []*dns.DNSEntry, add bool, allOrNothing bool) ([]*dns.EntryResult, error) {
 
+	if allOrNothing && !s.DNSManager.AtomicEntryChanges() {
+		err := fmt.Errorf("all_or_nothing is not supported: the DNS entry store cannot apply a batch atomically")
+		return nil, &configmapmanager.Error{Kind: configmapmanager.ErrFailedPrecondition, Err: err}
 	}
-	return &dns.AddServerResponse{}, nil
 
+	op := configmapmanager.EntryRemove
+	if add {
+		op = configmapmanager.EntryAdd
+	}
+	var changes []configmapmanager.EntryChange
+	var positions []int
+	results := make([]*dns.EntryResult, 0, len(entries))
+	invalid := false
+	for i, entry := range entries {
+		result := &dns.EntryResult{Entry: entry}
+		results = append(results, result)
+
+		entryKey, err := configmapmanager.GenerateKey(toDNSEntry(entry))
+		if err != nil {
+			result.Status, result.Message = dns.EntryStatus_ENTRY_STATUS_INVALID_KEY, err.Error()
+			invalid = true
+			continue
+		}
+		// Deleting without addresses removes the entry from every address it is registered on.
+		addresses, err := configmapmanager.NormalizeIPs(entryAddresses(entry))
+		if err == nil && len(addresses) == 0 && add {
+			err = fmt.Errorf("at least one IP address is required")
+		}
+		if err != nil {
+			result.Status, result.Message = dns.EntryStatus_ENTRY_STATUS_INVALID_IP, err.Error()
+			invalid = true
+			continue
+		}
+		changes = append(changes, configmapmanager.EntryChange{Op: op, DNSName: entryKey, IPAddresses: addresses})
+		positions = append(positions, i)
+	}
+
+	if allOrNothing && invalid {
+		for _, i := range positions {
+			results[i].Status, results[i].Message = dns.EntryStatus_ENTRY_STATUS_ABORTED, "batch aborted because another entry is invalid"
+		}
+		return results, nil
+	}
+	if len(changes) == 0 {
+		return results, nil
+	}
+
+	changeResults, err := s.DNSManager.ApplyEntryChanges(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+	for k, changeResult := range changeResults {
+		result := results[positions[k]]
+		switch {
+		case changeResult.Err != nil:
+			// The entries were validated already: any other error is one of the store.
+			return nil, changeResult.Err
+		case add && changeResult.Changed:
+			result.Status = dns.EntryStatus_ENTRY_STATUS_CREATED
+		case add:
+			result.Status = dns.EntryStatus_ENTRY_STATUS_ALREADY_EXISTS
+		case changeResult.Changed:
+			result.Status = dns.EntryStatus_ENTRY_STATUS_DELETED
+		default:
+			result.Status = dns.EntryStatus_ENTRY_STATUS_NOT_FOUND
+		}
+	}
+	return results, nil
+}
+
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+)
+
+// ListEntries returns the L2SM entries registered in the hosts plugin, sorted by scope, network, pod name and IP.
+// Names that were not generated by GenerateKey are skipped.
+func (s *server) ListEntries(ctx context.Context, req *dns.ListEntriesRequest) (*dns.ListEntriesResponse, error) {
+
+	records, err := s.DNSManager.ListDNSRecords(ctx)
+	if err != nil {
+		return &dns.ListEntriesResponse{}, statusError(err, "could not list entries", "")
+	}
+
+	entries := entriesFromRecords(records, req.GetNetwork(), req.GetScope())
+
+	// The page token is the cursor of the last entry returned, so pages stay consistent
+	// even if entries are added or removed between calls.
+	start := 0
+	if req.GetPageToken() != "" {
+		cursor, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+		if err != nil {
+			err = &configmapmanager.Error{Kind: configmapmanager.ErrInvalidArgument, Field: "page_token", Err: err}
+			return &dns.ListEntriesResponse{}, statusError(err, "invalid page token", "")
+		}
+		start = sort.Search(len(entries), func(i int) bool {
+			return entryCursor(entries[i]) > string(cursor)
+		})
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	} else if pageSize > maxListPageSize {
+		pageSize = maxListPageSize
+	}
+
+	end := start + pageSize
+	if end >= len(entries) {
+		return &dns.ListEntriesResponse{Entries: entries[start:]}, nil
+	}
+	nextToken := base64.RawURLEncoding.EncodeToString([]byte(entryCursor(entries[end-1])))
+	return &dns.ListEntriesResponse{Entries: entries[start:end], NextPageToken: nextToken}, nil
+}
+
+// WatchEntries streams the registered L2SM entries, followed by every later addition and deletion.
+// A client resuming from the resource version of the last event it received gets the changes since
+// then replayed, or a snapshot of the current entries if the server no longer remembers that version.
+func (s *server) WatchEntries(req *dns.WatchEntriesRequest, stream dns.DnsService_WatchEntriesServer) error {
+
+	ctx := stream.Context()
+	records, resourceVersion, events, err := s.DNSManager.WatchDNSRecords(ctx)
+	if err != nil {
+		return statusError(err, "could not watch entries", "")
+	}
+
+	// The snapshot and replayed events carry the requested version, so that a client cut off before
+	// SYNCED resumes from the version it had.
+	var replay []configmapmanager.RecordEvent
+	replayed := false
+	if req.GetResourceVersion() != "" {
+		replay, replayed = s.DNSManager.ReplayDNSRecords(req.GetResourceVersion(), records)
+	}
+	if replayed {
+		for _, event := range replay {
+			if err := sendRecordEvent(stream, event, req); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, entry := range entriesFromRecords(records, req.GetNetwork(), req.GetScope()) {
+			if err := stream.Send(&dns.WatchEntriesResponse{Type: dns.EventType_EVENT_TYPE_ADDED, Entry: entry, ResourceVersion: req.GetResourceVersion(), Snapshot: true}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := stream.Send(&dns.WatchEntriesResponse{Type: dns.EventType_EVENT_TYPE_SYNCED, ResourceVersion: resourceVersion, Snapshot: !replayed}); err != nil {
+		return err
+	}
+
+	for event := range events {
+		if err := sendRecordEvent(stream, event, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendRecordEvent sends the entry of a record event, unless it does not match the filters of req.
+func sendRecordEvent(stream dns.DnsService_WatchEntriesServer, event configmapmanager.RecordEvent, req *dns.WatchEntriesRequest) error {
+	eventType := dns.EventType_EVENT_TYPE_ADDED
+	if event.Type == configmapmanager.RecordDeleted {
+		eventType = dns.EventType_EVENT_TYPE_DELETED
+	}
+	entries := entriesFromRecords(map[string][]string{event.IPAddress: {event.DNSName}}, req.GetNetwork(), req.GetScope())
+	for _, entry := range entries {
+		if err := stream.Send(&dns.WatchEntriesResponse{Type: eventType, Entry: entry, ResourceVersion: event.ResourceVersion}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEntryChange applies a single change with ApplyEntryChanges, and returns its result with the
+// addresses ordered by sortAddresses.
+func (s *server) applyEntryChange(ctx context.Context, change configmapmanager.EntryChange) (configmapmanager.ChangeResult, error) {
+	results, err := s.DNSManager.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{change})
+	if err != nil {
+		return configmapmanager.ChangeResult{}, err
+	}
+	if results[0].Err != nil {
+		return configmapmanager.ChangeResult{}, results[0].Err
+	}
+	sortAddresses(results[0].IPAddresses)
+	return results[0], nil
+}
+
+// entriesFromRecords decodes the ip -> []names records into L2SM entries matching the network and scope
+// filters, sorted by entryCursor. Every address of a name is gathered into a single entry. Names that
+// were not generated by GenerateKey are skipped.
+func entriesFromRecords(records map[string][]string, network, scope string) []*dns.DNSEntry {
+	byName := make(map[string]*dns.DNSEntry)
+	for ip, names := range records {
+		for _, name := range names {
+			dnsEntry, err := configmapmanager.ParseKey(name)
+			if err != nil {
+				continue
+			}
+			if network != "" && network != dnsEntry.Network {
+				continue
+			}
+			if scope != "" && scope != dnsEntry.Scope {
+				continue
+			}
+			entry, found := byName[name]
+			if !found {
+				entry = &dns.DNSEntry{PodName: dnsEntry.PodName, Network: dnsEntry.Network, Scope: dnsEntry.Scope, Namespace: dnsEntry.Namespace, Cluster: dnsEntry.Cluster}
+				byName[name] = entry
+			}
+			entry.IpAddresses = append(entry.IpAddresses, ip)
+		}
+	}
+
+	entries := make([]*dns.DNSEntry, 0, len(byName))
+	for _, entry := range byName {
+		sortAddresses(entry.IpAddresses)
+		entry.IpAddress = entry.IpAddresses[0]
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entryCursor(entries[i]) < entryCursor(entries[j])
+	})
+	return entries
+}
+
+// toDNSEntry returns the naming fields of an API entry.
+func toDNSEntry(entry *dns.DNSEntry) configmapmanager.DNSEntry {
+	return configmapmanager.DNSEntry{
+		PodName:   entry.GetPodName(),
+		Network:   entry.GetNetwork(),
+		Scope:     entry.GetScope(),
+		Namespace: entry.GetNamespace(),
+		Cluster:   entry.GetCluster(),
+	}
+}
+
+// entryAddresses returns every address of the entry: ip_address followed by ip_addresses.
+func entryAddresses(entry *dns.DNSEntry) []string {
+	var addresses []string
+	if entry.GetIpAddress() != "" {
+		addresses = append(addresses, entry.GetIpAddress())
+	}
+	return append(addresses, entry.GetIpAddresses()...)
+}
+
+// sortAddresses orders IPv4 addresses before IPv6 ones, and each family by value, like the hosts
+// entries of the Corefile.
+func sortAddresses(addresses []string) {
+	slices.SortFunc(addresses, corefile.CompareIPs)
+}
+
+// entryCursor returns the sort key of an entry, used both for ordering and as page token.
+func entryCursor(entry *dns.DNSEntry) string {
+	return strings.Join([]string{entry.GetCluster(), entry.GetNamespace(), entry.GetScope(), entry.GetNetwork(), entry.GetPodName(), entry.GetIpAddress()}, " ")
+}
+
+// forwardPolicies maps the API forward policies to the CoreDNS ones.
+var forwardPolicies = map[dns.ForwardPolicy]string{
+	dns.ForwardPolicy_FORWARD_POLICY_UNSPECIFIED: "",
+	dns.ForwardPolicy_FORWARD_POLICY_RANDOM:      configmapmanager.ForwardPolicyRandom,
+	dns.ForwardPolicy_FORWARD_POLICY_ROUND_ROBIN: configmapmanager.ForwardPolicyRoundRobin,
+	dns.ForwardPolicy_FORWARD_POLICY_SEQUENTIAL:  configmapmanager.ForwardPolicySequential,
+}
+
+// toForwardConfig converts an API server into the forward configuration of its server block.
+// The serverDomain and serverPort fields give the first upstream.
+func toForwardConfig(server *dns.Server) configmapmanager.ForwardConfig {
+	policy, ok := forwardPolicies[server.GetPolicy()]
+	if !ok {
+		// Let validation reject unknown enum values instead of silently using the default.
+		policy = server.GetPolicy().String()
+	}
+	cfg := configmapmanager.ForwardConfig{
+		Policy:        policy,
+		HealthCheck:   server.GetHealthCheck().AsDuration(),
+		Expire:        server.GetExpire().AsDuration(),
+		TLS:           server.GetTls(),
+		TLSServerName: server.GetTlsServername(),
+	}
+	if server.GetServerDomain() != "" {
+		port := server.GetServerPort()
+		if port == "" {
+			port = "53"
+		}
+		cfg.Upstreams = append(cfg.Upstreams, net.JoinHostPort(server.GetServerDomain(), port))
+	}
+	cfg.Upstreams = append(cfg.Upstreams, server.GetUpstreams()...)
+	if server.MaxFails != nil {
+		maxFails := int(server.GetMaxFails())
+		cfg.MaxFails = &maxFails
+	}
+	return cfg
+}
+
+// toServer converts a forward server block into its API representation.
+func toServer(server configmapmanager.ForwardServer) *dns.Server {
+	resp := &dns.Server{
+		DomPort:       server.DomPort,
+		ServerDomain:  server.ServerDomain,
+		ServerPort:    server.ServerPort,
+		Tls:           server.Forward.TLS,
+		TlsServername: server.Forward.TLSServerName,
+	}
+	if len(server.Forward.Upstreams) > 1 {
+		resp.Upstreams = server.Forward.Upstreams[1:]
+	}
+	for policy, name := range forwardPolicies {
+		if name == server.Forward.Policy {
+			resp.Policy = policy
+		}
+	}
+	if server.Forward.HealthCheck > 0 {
+		resp.HealthCheck = durationpb.New(server.Forward.HealthCheck)
+	}
+	if server.Forward.Expire > 0 {
+		resp.Expire = durationpb.New(server.Forward.Expire)
+	}
+	if server.Forward.MaxFails != nil {
+		maxFails := int32(*server.Forward.MaxFails)
+		resp.MaxFails = &maxFails
+	}
+	return resp
+}
+
+// recordTypes maps the API record types to the manager ones.
+var recordTypes = map[dns.RecordType]string{
+	dns.RecordType_RECORD_TYPE_CNAME: configmapmanager.RecordTypeCNAME,
+	dns.RecordType_RECORD_TYPE_SRV:   configmapmanager.RecordTypeSRV,
+	dns.RecordType_RECORD_TYPE_TXT:   configmapmanager.RecordTypeTXT,
+}
+
+// toRecord converts an API record into a manager record.
+func toRecord(record *dns.Record) (configmapmanager.Record, error) {
+	recordType, ok := recordTypes[record.GetType()]
+	if !ok {
+		err := fmt.Errorf("unsupported record type %v", record.GetType())
+		return configmapmanager.Record{}, &configmapmanager.Error{Kind: configmapmanager.ErrInvalidArgument, Field: "type", Err: err}
+	}
+	for field, value := range map[string]uint32{"priority": record.GetPriority(), "weight": record.GetWeight(), "port": record.GetPort()} {
+		if value > math.MaxUint16 {
+			err := fmt.Errorf("SRV %s %d is out of range", field, value)
+			return configmapmanager.Record{}, &configmapmanager.Error{Kind: configmapmanager.ErrInvalidArgument, Field: field, Err: err}
+		}
+	}
+	return configmapmanager.Record{
+		Name:     record.GetName(),
+		Type:     recordType,
+		TTL:      record.GetTtl(),
+		Target:   record.GetTarget(),
+		Priority: uint16(record.GetPriority()),
+		Weight:   uint16(record.GetWeight()),
+		Port:     uint16(record.GetPort()),
+		Text:     record.GetText(),
+	}, nil
+}
+
+// fromRecord converts a manager record into its API representation.
+func fromRecord(record configmapmanager.Record) *dns.Record {
+	resp := &dns.Record{
+		Name:     record.Name,
+		Ttl:      record.TTL,
+		Target:   record.Target,
+		Priority: uint32(record.Priority),
+		Weight:   uint32(record.Weight),
+		Port:     uint32(record.Port),
+		Text:     record.Text,
+	}
+	for recordType, name := range recordTypes {
+		if name == record.Type {
+			resp.Type = recordType
+		}
+	}
+	return resp
 }
@@ -0,0 +1,726 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/propagation"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newTestServer returns a server backed by an in-memory manager holding the given ip -> []names
+// records.
+func newTestServer(t *testing.T, records map[string][]string) *server {
+	t.Helper()
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	if len(records) > 0 {
+		require.NoError(t, mgr.UpdateDNSRecords(context.Background(), records, nil))
+	}
+	return &server{DNSManager: mgr}
+}
+
+// ----------------------------------------------
+// AddEntry
+// ----------------------------------------------
+func TestAddEntryRejectsNegativeTTL(t *testing.T) {
+	s := newTestServer(t, nil)
+	ctx := context.Background()
+
+	_, err := s.AddEntry(ctx, &dns.AddEntryRequest{
+		Entry: &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.1"}},
+		Ttl:   durationpb.New(-time.Minute),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	records, err := s.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestAddEntryWaitTimeout(t *testing.T) {
+	// A resolver that reads every query and never answers.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			if _, _, err := conn.ReadFrom(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	s := newTestServer(t, nil)
+	s.waiter = &propagation.Waiter{Endpoint: conn.LocalAddr().String(), Interval: 10 * time.Millisecond}
+	ctx := context.Background()
+
+	start := time.Now()
+	_, err = s.AddEntry(ctx, &dns.AddEntryRequest{
+		Entry:       &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.1"}},
+		WaitTimeout: durationpb.New(200 * time.Millisecond),
+	})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Less(t, time.Since(start), time.Second, "the wait outlived its timeout")
+
+	// The entry is kept: only its propagation timed out.
+	records, err := s.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+}
+
+// configMapVersion returns the resourceVersion of the ConfigMap of s.
+func configMapVersion(t *testing.T, s *server) string {
+	t.Helper()
+	cfg, err := s.GetConfigMap(context.Background())
+	require.NoError(t, err)
+	return cfg.ResourceVersion
+}
+
+func TestAddEntryResponse(t *testing.T) {
+	s := newTestServer(t, nil)
+	ctx := context.Background()
+	entry := &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"fd00::1", "10.0.0.1"}}
+
+	resp, err := s.AddEntry(ctx, &dns.AddEntryRequest{Entry: entry})
+	require.NoError(t, err)
+	require.Equal(t, "pod-a.net1.global.l2sm", resp.GetFqdn())
+	require.Equal(t, []string{"10.0.0.1", "fd00::1"}, resp.GetIpAddresses())
+	require.True(t, resp.GetChanged())
+	version := configMapVersion(t, s)
+	require.Equal(t, version, resp.GetResourceVersion())
+
+	// Adding the same addresses again writes nothing.
+	resp, err = s.AddEntry(ctx, &dns.AddEntryRequest{Entry: &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"}})
+	require.NoError(t, err)
+	require.Equal(t, "pod-a.net1.global.l2sm", resp.GetFqdn())
+	require.Equal(t, []string{"10.0.0.1", "fd00::1"}, resp.GetIpAddresses())
+	require.False(t, resp.GetChanged())
+	require.Equal(t, version, resp.GetResourceVersion())
+	require.Equal(t, version, configMapVersion(t, s))
+
+	// A lease is always written, even on addresses registered already.
+	resp, err = s.AddEntry(ctx, &dns.AddEntryRequest{Entry: entry, Ttl: durationpb.New(time.Minute)})
+	require.NoError(t, err)
+	require.True(t, resp.GetChanged())
+	require.NotEqual(t, version, resp.GetResourceVersion())
+	require.Equal(t, configMapVersion(t, s), resp.GetResourceVersion())
+}
+
+// ----------------------------------------------
+// DeleteEntry
+// ----------------------------------------------
+func TestDeleteEntryResponse(t *testing.T) {
+	s := newTestServer(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"fd00::1":  {"pod-a.net1.global.l2sm"},
+	})
+	ctx := context.Background()
+	entry := func(ips ...string) *dns.DeleteEntryRequest {
+		return &dns.DeleteEntryRequest{Entry: &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: ips}}
+	}
+	version := configMapVersion(t, s)
+
+	// Deleting an address the entry is not registered on writes nothing.
+	resp, err := s.DeleteEntry(ctx, entry("10.0.0.9"))
+	require.NoError(t, err)
+	require.Equal(t, "pod-a.net1.global.l2sm", resp.GetFqdn())
+	require.Equal(t, []string{"10.0.0.1", "fd00::1"}, resp.GetIpAddresses())
+	require.False(t, resp.GetChanged())
+	require.Equal(t, version, resp.GetResourceVersion())
+	require.Equal(t, version, configMapVersion(t, s))
+
+	resp, err = s.DeleteEntry(ctx, entry("10.0.0.1"))
+	require.NoError(t, err)
+	require.Equal(t, "pod-a.net1.global.l2sm", resp.GetFqdn())
+	require.Equal(t, []string{"fd00::1"}, resp.GetIpAddresses())
+	require.True(t, resp.GetChanged())
+	require.NotEqual(t, version, resp.GetResourceVersion())
+	require.Equal(t, configMapVersion(t, s), resp.GetResourceVersion())
+
+	// Without addresses, every remaining one is deleted.
+	resp, err = s.DeleteEntry(ctx, entry())
+	require.NoError(t, err)
+	require.Empty(t, resp.GetIpAddresses())
+	require.True(t, resp.GetChanged())
+	require.Equal(t, configMapVersion(t, s), resp.GetResourceVersion())
+}
+
+// ----------------------------------------------
+// AddServer
+// ----------------------------------------------
+func TestAddServerResponse(t *testing.T) {
+	s := newTestServer(t, nil)
+	ctx := context.Background()
+	req := &dns.AddServerRequest{Server: &dns.Server{DomPort: "peer.org:53", ServerDomain: "10.1.0.53", ServerPort: "53"}}
+
+	resp, err := s.AddServer(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "peer.org:53", resp.GetServer().GetDomPort())
+	require.Equal(t, "10.1.0.53", resp.GetServer().GetServerDomain())
+	require.Equal(t, "53", resp.GetServer().GetServerPort())
+	require.True(t, resp.GetChanged())
+	version := configMapVersion(t, s)
+	require.Equal(t, version, resp.GetResourceVersion())
+
+	// Adding the same server again writes nothing.
+	resp, err = s.AddServer(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "peer.org:53", resp.GetServer().GetDomPort())
+	require.False(t, resp.GetChanged())
+	require.Equal(t, version, resp.GetResourceVersion())
+	require.Equal(t, version, configMapVersion(t, s))
+
+	req.Server.ServerPort = "5353"
+	resp, err = s.AddServer(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "5353", resp.GetServer().GetServerPort())
+	require.True(t, resp.GetChanged())
+	require.Equal(t, configMapVersion(t, s), resp.GetResourceVersion())
+}
+
+// ----------------------------------------------
+// ListEntries
+// ----------------------------------------------
+func TestListEntriesFilters(t *testing.T) {
+	s := newTestServer(t, map[string][]string{
+		"10.0.0.10":   {"pod-a.net1.global.l2sm"},
+		"10.0.0.9":    {"pod-a.net1.global.l2sm"},
+		"fd00::1":     {"pod-a.net1.global.l2sm"},
+		"10.0.0.2":    {"pod-b.net2.global.l2sm"},
+		"10.0.0.3":    {"pod-c.net1.local.l2sm"},
+		"10.0.0.4":    {"not-an-l2sm-name"},
+		"192.168.0.1": {"pod-d.net2.local.l2sm"},
+	})
+	ctx := context.Background()
+
+	names := func(resp *dns.ListEntriesResponse) []string {
+		var out []string
+		for _, entry := range resp.GetEntries() {
+			out = append(out, fmt.Sprintf("%s.%s.%s", entry.GetPodName(), entry.GetNetwork(), entry.GetScope()))
+		}
+		return out
+	}
+
+	resp, err := s.ListEntries(ctx, &dns.ListEntriesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod-a.net1.global", "pod-b.net2.global", "pod-c.net1.local", "pod-d.net2.local"}, names(resp))
+	require.Empty(t, resp.GetNextPageToken())
+	// Every address of a name is gathered into its entry, IPv4 first and by value, as in the Corefile.
+	require.Equal(t, "10.0.0.9", resp.GetEntries()[0].GetIpAddress())
+	require.Equal(t, []string{"10.0.0.9", "10.0.0.10", "fd00::1"}, resp.GetEntries()[0].GetIpAddresses())
+
+	resp, err = s.ListEntries(ctx, &dns.ListEntriesRequest{Network: "net1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod-a.net1.global", "pod-c.net1.local"}, names(resp))
+
+	resp, err = s.ListEntries(ctx, &dns.ListEntriesRequest{Scope: "local"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod-c.net1.local", "pod-d.net2.local"}, names(resp))
+
+	resp, err = s.ListEntries(ctx, &dns.ListEntriesRequest{Network: "net2", Scope: "global"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pod-b.net2.global"}, names(resp))
+
+	resp, err = s.ListEntries(ctx, &dns.ListEntriesRequest{Network: "missing"})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetEntries())
+}
+
+func TestListEntriesPagination(t *testing.T) {
+	const total = maxListPageSize + 5
+	records := make(map[string][]string, total)
+	for i := 0; i < total; i++ {
+		records[fmt.Sprintf("10.0.%d.%d", i/250, i%250+1)] = []string{fmt.Sprintf("pod-%04d.net1.global.l2sm", i)}
+	}
+	s := newTestServer(t, records)
+	ctx := context.Background()
+
+	t.Run("default page size", func(t *testing.T) {
+		resp, err := s.ListEntries(ctx, &dns.ListEntriesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.GetEntries(), defaultListPageSize)
+		require.NotEmpty(t, resp.GetNextPageToken())
+	})
+
+	t.Run("page size is capped", func(t *testing.T) {
+		resp, err := s.ListEntries(ctx, &dns.ListEntriesRequest{PageSize: 5000})
+		require.NoError(t, err)
+		require.Len(t, resp.GetEntries(), maxListPageSize)
+		require.NotEmpty(t, resp.GetNextPageToken())
+	})
+
+	t.Run("tokens walk every entry once", func(t *testing.T) {
+		var seen []string
+		req := &dns.ListEntriesRequest{PageSize: 300}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 10, "too many pages")
+			resp, err := s.ListEntries(ctx, req)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(resp.GetEntries()), 300)
+			for _, entry := range resp.GetEntries() {
+				seen = append(seen, entry.GetPodName())
+			}
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = resp.GetNextPageToken()
+		}
+		require.Len(t, seen, total)
+		for i, name := range seen {
+			require.Equal(t, fmt.Sprintf("pod-%04d", i), name)
+		}
+	})
+
+	t.Run("tokens survive concurrent changes", func(t *testing.T) {
+		resp, err := s.ListEntries(ctx, &dns.ListEntriesRequest{PageSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, "pod-0001", resp.GetEntries()[1].GetPodName())
+
+		// Removing an entry of the first page does not shift the next one.
+		require.NoError(t, s.DNSManager.RemoveDNSEntry(ctx, "pod-0000.net1.global.l2sm"))
+		resp, err = s.ListEntries(ctx, &dns.ListEntriesRequest{PageSize: 2, PageToken: resp.GetNextPageToken()})
+		require.NoError(t, err)
+		require.Equal(t, "pod-0002", resp.GetEntries()[0].GetPodName())
+		require.Equal(t, "pod-0003", resp.GetEntries()[1].GetPodName())
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := s.ListEntries(ctx, &dns.ListEntriesRequest{PageToken: "not base64!"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// ----------------------------------------------
+// BatchAddEntries and BatchDeleteEntries
+// ----------------------------------------------
+func batchStatuses(results []*dns.EntryResult) []dns.EntryStatus {
+	var out []dns.EntryStatus
+	for _, result := range results {
+		out = append(out, result.GetStatus())
+	}
+	return out
+}
+
+func TestBatchAddEntries(t *testing.T) {
+	s := newTestServer(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}})
+	ctx := context.Background()
+
+	resp, err := s.BatchAddEntries(ctx, &dns.BatchAddEntriesRequest{Entries: []*dns.DNSEntry{
+		{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"},
+		{PodName: "pod-b", Network: "net1", Scope: "global", IpAddress: "10.0.0.2"},
+		{PodName: "pod-c", Network: "net1", IpAddress: "10.0.0.3"},
+		{PodName: "pod-d", Network: "net1", Scope: "global", IpAddress: "not-an-ip"},
+		{PodName: "pod-e", Network: "net1", Scope: "global"},
+		// Only its second address is new.
+		{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.1", "fd00::1"}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_ALREADY_EXISTS,
+		dns.EntryStatus_ENTRY_STATUS_CREATED,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_KEY,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_IP,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_IP,
+		dns.EntryStatus_ENTRY_STATUS_CREATED,
+	}, batchStatuses(resp.GetResults()))
+	// Results echo their entry, and explain the invalid ones.
+	require.Equal(t, "pod-d", resp.GetResults()[3].GetEntry().GetPodName())
+	require.NotEmpty(t, resp.GetResults()[2].GetMessage())
+	require.NotEmpty(t, resp.GetResults()[3].GetMessage())
+
+	records, err := s.DNSManager.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+		"fd00::1":  {"pod-a.net1.global.l2sm"},
+	}, records)
+}
+
+func TestBatchAddEntriesDuplicates(t *testing.T) {
+	s := newTestServer(t, nil)
+	ctx := context.Background()
+
+	// An entry repeated within the batch sees the effect of the earlier one.
+	entry := &dns.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"}
+	resp, err := s.BatchAddEntries(ctx, &dns.BatchAddEntriesRequest{Entries: []*dns.DNSEntry{entry, entry}})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_CREATED,
+		dns.EntryStatus_ENTRY_STATUS_ALREADY_EXISTS,
+	}, batchStatuses(resp.GetResults()))
+
+	records, err := s.DNSManager.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+
+	delResp, err := s.BatchDeleteEntries(ctx, &dns.BatchDeleteEntriesRequest{Entries: []*dns.DNSEntry{entry, entry}})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_DELETED,
+		dns.EntryStatus_ENTRY_STATUS_NOT_FOUND,
+	}, batchStatuses(delResp.GetResults()))
+
+	records, err = s.DNSManager.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestBatchDeleteEntries(t *testing.T) {
+	s := newTestServer(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+		"fd00::2":  {"pod-b.net1.global.l2sm"},
+		"10.0.0.3": {"pod-c.net1.global.l2sm"},
+	})
+	ctx := context.Background()
+
+	resp, err := s.BatchDeleteEntries(ctx, &dns.BatchDeleteEntriesRequest{Entries: []*dns.DNSEntry{
+		{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"},
+		// Without addresses, every address of the entry is removed.
+		{PodName: "pod-b", Network: "net1", Scope: "global"},
+		{PodName: "pod-c", Network: "net1", Scope: "global", IpAddress: "10.0.0.9"},
+		{PodName: "missing", Network: "net1", Scope: "global"},
+		{PodName: "pod-c", Scope: "global", IpAddress: "10.0.0.3"},
+		{PodName: "pod-c", Network: "net1", Scope: "global", IpAddress: "not-an-ip"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_DELETED,
+		dns.EntryStatus_ENTRY_STATUS_DELETED,
+		dns.EntryStatus_ENTRY_STATUS_NOT_FOUND,
+		dns.EntryStatus_ENTRY_STATUS_NOT_FOUND,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_KEY,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_IP,
+	}, batchStatuses(resp.GetResults()))
+
+	records, err := s.DNSManager.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.3": {"pod-c.net1.global.l2sm"}}, records)
+}
+
+func TestBatchAllOrNothing(t *testing.T) {
+	s := newTestServer(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}})
+	ctx := context.Background()
+	before, err := s.DNSManager.GetConfigMap(ctx)
+	require.NoError(t, err)
+
+	addResp, err := s.BatchAddEntries(ctx, &dns.BatchAddEntriesRequest{AllOrNothing: true, Entries: []*dns.DNSEntry{
+		{PodName: "pod-b", Network: "net1", Scope: "global", IpAddress: "10.0.0.2"},
+		{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"},
+		{PodName: "pod-c", Network: "net1", Scope: "global", IpAddress: "not-an-ip"},
+	}})
+	require.NoError(t, err)
+	// Nothing is written, so every valid entry is aborted, even one that is registered already.
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_ABORTED,
+		dns.EntryStatus_ENTRY_STATUS_ABORTED,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_IP,
+	}, batchStatuses(addResp.GetResults()))
+
+	delResp, err := s.BatchDeleteEntries(ctx, &dns.BatchDeleteEntriesRequest{AllOrNothing: true, Entries: []*dns.DNSEntry{
+		{PodName: "pod-a", Network: "net1", Scope: "global"},
+		{PodName: "pod-b", Network: "net1", Scope: "global"},
+		{PodName: "pod-c", Network: "net1"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{
+		dns.EntryStatus_ENTRY_STATUS_ABORTED,
+		dns.EntryStatus_ENTRY_STATUS_ABORTED,
+		dns.EntryStatus_ENTRY_STATUS_INVALID_KEY,
+	}, batchStatuses(delResp.GetResults()))
+
+	// Nothing was written.
+	after, err := s.DNSManager.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before.ResourceVersion, after.ResourceVersion)
+}
+
+// nonAtomicManager is a DNSManager that cannot apply a batch atomically, like the L2SMDNSEntry store.
+type nonAtomicManager struct {
+	configmapmanager.DNSManager
+}
+
+func (nonAtomicManager) AtomicEntryChanges() bool { return false }
+
+func TestBatchAllOrNothingNeedsAtomicStore(t *testing.T) {
+	s := newTestServer(t, nil)
+	s.DNSManager = nonAtomicManager{s.DNSManager}
+	ctx := context.Background()
+	entries := []*dns.DNSEntry{{PodName: "pod-a", Network: "net1", Scope: "global", IpAddress: "10.0.0.1"}}
+
+	_, err := s.BatchAddEntries(ctx, &dns.BatchAddEntriesRequest{AllOrNothing: true, Entries: entries})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.BatchDeleteEntries(ctx, &dns.BatchDeleteEntriesRequest{AllOrNothing: true, Entries: entries})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	records, err := s.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	// Without all_or_nothing, the entries are applied one by one.
+	resp, err := s.BatchAddEntries(ctx, &dns.BatchAddEntriesRequest{Entries: entries})
+	require.NoError(t, err)
+	require.Equal(t, []dns.EntryStatus{dns.EntryStatus_ENTRY_STATUS_CREATED}, batchStatuses(resp.GetResults()))
+}
+
+// ----------------------------------------------
+// WatchEntries
+// ----------------------------------------------
+
+// watchStream is a WatchEntries server stream that hands the responses to the test.
+type watchStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses chan *dns.WatchEntriesResponse
+}
+
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+func (w *watchStream) Send(resp *dns.WatchEntriesResponse) error {
+	select {
+	case w.responses <- resp:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// watchEntries runs WatchEntries in the background, until the returned function cuts the stream.
+func watchEntries(t *testing.T, s *server, req *dns.WatchEntriesRequest) (*watchStream, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream := &watchStream{ctx: ctx, responses: make(chan *dns.WatchEntriesResponse)}
+	go func() { _ = s.WatchEntries(req, stream) }()
+	return stream, cancel
+}
+
+// next returns the next response as "TYPE pod.network.scope ips resourceVersion snapshot", with
+// the version replaced by its position in versions.
+func (w *watchStream) next(t *testing.T, versions ...string) string {
+	t.Helper()
+	select {
+	case resp := <-w.responses:
+		entry := resp.GetEntry()
+		version := resp.GetResourceVersion()
+		for i, v := range versions {
+			if v == version {
+				version = fmt.Sprintf("v%d", i)
+			}
+		}
+		return fmt.Sprintf("%s %s.%s.%s %v %s %t", resp.GetType(), entry.GetPodName(), entry.GetNetwork(), entry.GetScope(), entry.GetIpAddresses(), version, resp.GetSnapshot())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a WatchEntries response")
+		return ""
+	}
+}
+
+func TestWatchEntriesSnapshot(t *testing.T) {
+	s := newTestServer(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"fd00::1":  {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net2.global.l2sm"},
+		"10.0.0.3": {"pod-c.net1.local.l2sm", "not-an-l2sm-name"},
+	})
+	ctx := context.Background()
+	current := configMapVersion(t, s)
+
+	// The snapshot holds the entries matching the filters and carries the requested version. SYNCED
+	// carries the current one.
+	stream, _ := watchEntries(t, s, &dns.WatchEntriesRequest{Network: "net1", Scope: "global"})
+	require.Equal(t, "EVENT_TYPE_ADDED pod-a.net1.global [10.0.0.1 fd00::1]  true", stream.next(t))
+	require.Equal(t, "EVENT_TYPE_SYNCED .. [] v0 true", stream.next(t, current))
+
+	// Later changes are filtered the same way.
+	require.NoError(t, s.AddDNSEntry(ctx, "pod-d.net2.global.l2sm", "10.0.0.4"))
+	require.NoError(t, s.AddDNSEntry(ctx, "pod-e.net1.global.l2sm", "10.0.0.5"))
+	added := configMapVersion(t, s)
+	require.NoError(t, s.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm", "fd00::1"))
+	latest := configMapVersion(t, s)
+	require.Equal(t, "EVENT_TYPE_ADDED pod-e.net1.global [10.0.0.5] v0 false", stream.next(t, added))
+	require.Equal(t, "EVENT_TYPE_DELETED pod-a.net1.global [fd00::1] v0 false", stream.next(t, latest))
+
+	// A version the server does not know gets a snapshot too.
+	stream, _ = watchEntries(t, s, &dns.WatchEntriesRequest{Network: "net1", ResourceVersion: "unknown"})
+	require.Equal(t, "EVENT_TYPE_ADDED pod-a.net1.global [10.0.0.1] v0 true", stream.next(t, "unknown"))
+	require.Equal(t, "EVENT_TYPE_ADDED pod-e.net1.global [10.0.0.5] v0 true", stream.next(t, "unknown"))
+	require.Equal(t, "EVENT_TYPE_ADDED pod-c.net1.local [10.0.0.3] v0 true", stream.next(t, "unknown"))
+	require.Equal(t, "EVENT_TYPE_SYNCED .. [] v0 true", stream.next(t, latest))
+}
+
+func TestWatchEntriesResume(t *testing.T) {
+	s := newTestServer(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+	})
+	ctx := context.Background()
+
+	stream, cut := watchEntries(t, s, &dns.WatchEntriesRequest{})
+	require.Equal(t, "EVENT_TYPE_ADDED pod-a.net1.global [10.0.0.1]  true", stream.next(t))
+	require.Equal(t, "EVENT_TYPE_ADDED pod-b.net1.global [10.0.0.2]  true", stream.next(t))
+	stream.next(t)
+
+	// The stream is cut after the first event of a version, and the entries change again meanwhile.
+	require.NoError(t, s.UpdateDNSRecords(ctx,
+		map[string][]string{"10.0.0.3": {"pod-c.net1.global.l2sm"}},
+		map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}))
+	cutAt := configMapVersion(t, s)
+	require.Equal(t, "EVENT_TYPE_DELETED pod-a.net1.global [10.0.0.1] v0 false", stream.next(t, cutAt))
+	cut()
+	require.NoError(t, s.AddDNSEntry(ctx, "pod-d.net1.global.l2sm", "10.0.0.4"))
+	latest := configMapVersion(t, s)
+
+	// Resuming replays the whole version the stream was cut in, and the changes since then, without
+	// a snapshot.
+	stream, _ = watchEntries(t, s, &dns.WatchEntriesRequest{ResourceVersion: cutAt})
+	require.Equal(t, "EVENT_TYPE_DELETED pod-a.net1.global [10.0.0.1] v0 false", stream.next(t, cutAt))
+	require.Equal(t, "EVENT_TYPE_ADDED pod-c.net1.global [10.0.0.3] v0 false", stream.next(t, cutAt))
+	require.Equal(t, "EVENT_TYPE_ADDED pod-d.net1.global [10.0.0.4] v0 false", stream.next(t, cutAt))
+	require.Equal(t, "EVENT_TYPE_SYNCED .. [] v0 false", stream.next(t, latest))
+
+	// Resuming at the current version replays nothing.
+	stream, _ = watchEntries(t, s, &dns.WatchEntriesRequest{ResourceVersion: latest})
+	require.Equal(t, "EVENT_TYPE_SYNCED .. [] v0 false", stream.next(t, latest))
+}
+
+// ----------------------------------------------
+// Status errors
+// ----------------------------------------------
+
+// newConfigMapServer returns a server backed by a ConfigMap manager over a fake client holding
+// objs, whose Update calls go through update if it is set.
+func newConfigMapServer(t *testing.T, update func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error, objs ...client.Object) *server {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	fclient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{Update: update}).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	return &server{DNSManager: mgr}
+}
+
+func testConfigMap(corefile string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "test-namespace"},
+		Data:       map[string]string{"Corefile": corefile},
+	}
+}
+
+// badRequestFields returns the fields of the BadRequest violations carried by err.
+func badRequestFields(t *testing.T, err error) []string {
+	t.Helper()
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		require.True(t, ok, "unexpected detail %T", detail)
+		for _, violation := range badRequest.GetFieldViolations() {
+			require.NotEmpty(t, violation.GetDescription())
+			fields = append(fields, violation.GetField())
+		}
+	}
+	return fields
+}
+
+func TestStatusErrors(t *testing.T) {
+	ctx := context.Background()
+	entry := func(podName string, ips ...string) *dns.AddEntryRequest {
+		return &dns.AddEntryRequest{Entry: &dns.DNSEntry{PodName: podName, Network: "net1", Scope: "global", IpAddresses: ips}}
+	}
+
+	tests := []struct {
+		name   string
+		server func(t *testing.T) *server
+		req    *dns.AddEntryRequest
+		code   codes.Code
+		fields []string
+	}{
+		{
+			name:   "invalid IP",
+			server: func(t *testing.T) *server { return newTestServer(t, nil) },
+			req:    entry("pod-a", "not-an-ip"),
+			code:   codes.InvalidArgument,
+			fields: []string{"entry.ip_address"},
+		},
+		{
+			name:   "invalid label",
+			server: func(t *testing.T) *server { return newTestServer(t, nil) },
+			req:    entry("Pod_A", "10.0.0.1"),
+			code:   codes.InvalidArgument,
+			fields: []string{"entry.pod_name"},
+		},
+		{
+			name:   "missing ConfigMap",
+			server: func(t *testing.T) *server { return newConfigMapServer(t, nil) },
+			req:    entry("pod-a", "10.0.0.1"),
+			code:   codes.NotFound,
+		},
+		{
+			name: "missing hosts plugin",
+			server: func(t *testing.T) *server {
+				return &server{DNSManager: configmapmanager.NewMemoryDNSManager(".:53 {\n    log\n}\n")}
+			},
+			req:  entry("pod-a", "10.0.0.1"),
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "conflict",
+			server: func(t *testing.T) *server {
+				// Every update loses the race against another writer, until the retries run out.
+				conflict := func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), errors.New("modified"))
+				}
+				return newConfigMapServer(t, conflict, testConfigMap(".:53 {\n    hosts {\n    }\n}\n"))
+			},
+			req:  entry("pod-a", "10.0.0.1"),
+			code: codes.Aborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.server(t).AddEntry(ctx, tt.req)
+			require.Error(t, err)
+			require.Equal(t, tt.code, status.Code(err), err.Error())
+			require.Equal(t, tt.fields, badRequestFields(t, err))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusCodes maps the kinds of the DNSManager errors to gRPC codes. Other errors are Unknown.
+var statusCodes = map[error]codes.Code{
+	configmapmanager.ErrInvalidArgument:    codes.InvalidArgument,
+	configmapmanager.ErrNotFound:           codes.NotFound,
+	configmapmanager.ErrAlreadyExists:      codes.AlreadyExists,
+	configmapmanager.ErrFailedPrecondition: codes.FailedPrecondition,
+	configmapmanager.ErrConflict:           codes.Aborted,
+	configmapmanager.ErrUnavailable:        codes.Unavailable,
+}
+
+// requestFields are the fields of the requests themselves, rather than of their entry, record or
+// server.
+var requestFields = map[string]bool{
+	"ttl":          true,
+	"wait_timeout": true,
+	"dom_port":     true,
+	"page_token":   true,
+}
+
+// statusError returns err as a gRPC status with the code of its kind and the message
+// "<message>. err: <err>". Invalid arguments carry a BadRequest detail with the offending field,
+// under the parent field of the request, e.g. "entry.ip_address".
+func statusError(err error, message, parent string) error {
+	kind := configmapmanager.ErrorKind(err)
+	code, ok := statusCodes[kind]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.Newf(code, "%s. err: %v", message, err)
+	if kind != configmapmanager.ErrInvalidArgument {
+		return st.Err()
+	}
+
+	field := configmapmanager.ErrorField(err)
+	switch {
+	case field == "":
+		field = parent
+	case requestFields[field] || parent == "":
+	default:
+		field = parent + "." + field
+	}
+	detailed, detailsErr := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
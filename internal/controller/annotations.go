@@ -0,0 +1,140 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// L2SMNetworksAnnotation lists the L2SM networks a pod is attached to, either as a
+	// comma-separated list of names or as a JSON list of {"name": ..., "ips": [...]} objects.
+	L2SMNetworksAnnotation = "l2sm/networks"
+	// NetworkStatusAnnotation is the Multus network-status annotation, used to find the
+	// addresses of L2SM attachments that do not set them explicitly.
+	NetworkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+)
+
+// l2smNetwork is an element of the JSON form of the L2SMNetworksAnnotation.
+type l2smNetwork struct {
+	Name string   `json:"name"`
+	IPs  []string `json:"ips,omitempty"`
+}
+
+// networkStatus is an element of the Multus NetworkStatusAnnotation.
+type networkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// hasL2SMNetworks reports whether the pod requests any L2SM network.
+func hasL2SMNetworks(annotations map[string]string) bool {
+	return strings.TrimSpace(annotations[L2SMNetworksAnnotation]) != ""
+}
+
+// l2smAttachments returns the addresses of the pod on each of its L2SM networks. Addresses set in
+// the L2SM annotation take precedence; otherwise they are taken from the Multus network-status entry
+// whose name, with or without its "<namespace>/" prefix, matches the network. Networks without any
+// known address yet are omitted.
+func l2smAttachments(pod *corev1.Pod) (map[string][]string, error) {
+	raw := strings.TrimSpace(pod.Annotations[L2SMNetworksAnnotation])
+	if raw == "" {
+		return map[string][]string{}, nil
+	}
+
+	var networks []l2smNetwork
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &networks); err != nil {
+			return nil, fmt.Errorf("could not parse %s annotation: %v", L2SMNetworksAnnotation, err)
+		}
+	} else {
+		for _, name := range strings.Split(raw, ",") {
+			networks = append(networks, l2smNetwork{Name: strings.TrimSpace(name)})
+		}
+	}
+
+	var statuses []networkStatus
+	if rawStatus := pod.Annotations[NetworkStatusAnnotation]; rawStatus != "" {
+		if err := json.Unmarshal([]byte(rawStatus), &statuses); err != nil {
+			return nil, fmt.Errorf("could not parse %s annotation: %v", NetworkStatusAnnotation, err)
+		}
+	}
+
+	attachments := make(map[string][]string)
+	for _, network := range networks {
+		if network.Name == "" {
+			continue
+		}
+		ips := network.IPs
+		if len(ips) == 0 {
+			for _, status := range statuses {
+				if status.Default {
+					continue
+				}
+				if status.Name == network.Name || strings.HasSuffix(status.Name, "/"+network.Name) {
+					ips = append(ips, status.IPs...)
+				}
+			}
+		}
+		for _, ip := range ips {
+			// Addresses may be given in CIDR notation.
+			if addr, _, err := net.ParseCIDR(ip); err == nil {
+				ip = addr.String()
+			}
+			attachments[network.Name] = append(attachments[network.Name], ip)
+		}
+	}
+	return attachments, nil
+}
+
+// podAddresses returns every known address of the pod, in canonical form: its cluster addresses,
+// those of its Multus attachments and those set in its L2SM annotation. Malformed annotations and
+// addresses are skipped.
+func podAddresses(pod *corev1.Pod) map[string]bool {
+	var ips []string
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+	var statuses []networkStatus
+	if err := json.Unmarshal([]byte(pod.Annotations[NetworkStatusAnnotation]), &statuses); err == nil {
+		for _, status := range statuses {
+			ips = append(ips, status.IPs...)
+		}
+	}
+	if attachments, err := l2smAttachments(pod); err == nil {
+		for _, addresses := range attachments {
+			ips = append(ips, addresses...)
+		}
+	}
+
+	addresses := make(map[string]bool)
+	for _, ip := range ips {
+		if addr, _, err := net.ParseCIDR(ip); err == nil {
+			ip = addr.String()
+		}
+		if normalized, err := configmapmanager.NormalizeIP(ip); err == nil {
+			addresses[normalized] = true
+		}
+	}
+	return addresses
+}
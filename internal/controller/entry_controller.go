@@ -0,0 +1,100 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1alpha1"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EntryReconciler renders the set of L2SMDNSEntry resources into the Corefile: every record of the
+// inter-domain hosts plugin is backed by an entry, and every entry has its records. Records that no
+// entry backs are removed, so the entries are the only source of truth, but only once the records
+// registered before the store was enabled were imported as entries.
+type EntryReconciler struct {
+	client.Client
+	// DNSManager is the ConfigMap-backed manager the records are written to.
+	DNSManager configmapmanager.DNSManager
+}
+
+// Reconcile syncs every record at once, whichever entry changed, so it converges even after missed
+// events.
+func (r *EntryReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	entries := &v1alpha1.L2SMDNSEntryList{}
+	if err := r.List(ctx, entries); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list L2SMDNSEntries: %w", err)
+	}
+	desired := configmapmanager.EntryRecords(entries.Items)
+
+	records, err := r.DNSManager.ListDNSRecords(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list DNS records: %w", err)
+	}
+
+	additions, removals := diffRecords(records, desired)
+	cfg, err := r.DNSManager.GetConfigMap(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not get ConfigMap: %w", err)
+	}
+	if !configmapmanager.EntriesImported(cfg) && len(removals) > 0 {
+		logger.Info("keeping the records no L2SMDNSEntry backs until they are imported", "records", removals)
+		removals = nil
+	}
+	if len(additions) == 0 && len(removals) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if err := r.DNSManager.UpdateDNSRecords(ctx, additions, removals); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not update DNS records: %w", err)
+	}
+	logger.Info("rendered L2SMDNSEntries", "added", additions, "removed", removals)
+	return ctrl.Result{}, nil
+}
+
+// diffRecords returns the ip -> []names records to add and to remove to turn current into desired.
+func diffRecords(current, desired map[string][]string) (map[string][]string, map[string][]string) {
+	additions := make(map[string][]string)
+	for ip, names := range desired {
+		for _, name := range names {
+			if !contains(current[ip], name) {
+				additions[ip] = append(additions[ip], name)
+			}
+		}
+	}
+	removals := make(map[string][]string)
+	for ip, names := range current {
+		for _, name := range names {
+			if !contains(desired[ip], name) {
+				removals[ip] = append(removals[ip], name)
+			}
+		}
+	}
+	return additions, removals
+}
+
+// SetupWithManager registers the reconciler for L2SMDNSEntry resources.
+func (r *EntryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("l2sm-dns-entry").
+		For(&v1alpha1.L2SMDNSEntry{}).
+		Complete(r)
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// PodReconciler keeps the DNS entries of every pod in sync with its L2SM network attachments:
+// an entry is registered for each attached L2SM network, and removed once the pod is deleted,
+// terminates or loses the attachment.
+type PodReconciler struct {
+	client.Client
+	DNSManager configmapmanager.DNSManager
+	// Scope and Cluster fill the corresponding DNSEntry fields of the generated names.
+	Scope   string
+	Cluster string
+	// Names generates the DNS names of the pods, DefaultNameTemplate if nil. It must include the
+	// Namespace field: otherwise pods of the same name in different namespaces would share their
+	// names, and deleting one would remove the entries of the others.
+	Names *configmapmanager.NameTemplate
+}
+
+// names returns the naming template of the reconciler, or an error if it cannot tell pods of
+// different namespaces apart.
+func (r *PodReconciler) names() (*configmapmanager.NameTemplate, error) {
+	names := r.Names
+	if names == nil {
+		var err error
+		if names, err = configmapmanager.DefaultNameTemplate(); err != nil {
+			return nil, err
+		}
+	}
+	if !slices.Contains(names.Fields(), "Namespace") {
+		return nil, fmt.Errorf("the pod controller needs a naming template that includes {{.Namespace}}, got one with fields %v", names.Fields())
+	}
+	return names, nil
+}
+
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	names, err := r.names()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired := make(map[string][]string)
+	pod := &corev1.Pod{}
+	err = r.Get(ctx, req.NamespacedName, pod)
+	switch {
+	case apierrors.IsNotFound(err):
+		// The pod is gone: every entry it owned is removed below.
+	case err != nil:
+		return ctrl.Result{}, err
+	case pod.DeletionTimestamp != nil, pod.Status.Phase == corev1.PodSucceeded, pod.Status.Phase == corev1.PodFailed:
+		// The pod is terminating: its entries are removed as well.
+	default:
+		attachments, err := l2smAttachments(pod)
+		if err != nil {
+			// A malformed annotation will not fix itself by retrying.
+			logger.Error(err, "ignoring L2SM networks of pod")
+			break
+		}
+		for network, ips := range attachments {
+			key, err := names.GenerateKey(r.dnsEntry(req.Namespace, req.Name, network))
+			if err != nil {
+				logger.Error(err, "could not generate DNS name", "network", network)
+				continue
+			}
+			addresses, err := configmapmanager.NormalizeIPs(ips)
+			if err != nil {
+				logger.Error(err, "ignoring invalid address", "network", network)
+				continue
+			}
+			for _, ip := range addresses {
+				desired[ip] = append(desired[ip], key)
+			}
+		}
+	}
+
+	records, err := r.DNSManager.ListDNSRecords(ctx)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not list DNS records: %w", err)
+	}
+
+	additions, removals := r.diffPodRecords(names, req.Namespace, req.Name, records, desired)
+	if len(additions) == 0 && len(removals) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if err := r.DNSManager.UpdateDNSRecords(ctx, additions, removals); err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not update DNS records: %w", err)
+	}
+	logger.Info("updated pod DNS entries", "added", additions, "removed", removals)
+	return ctrl.Result{}, nil
+}
+
+// dnsEntry returns the naming fields of the pod's attachment to network.
+func (r *PodReconciler) dnsEntry(namespace, name, network string) configmapmanager.DNSEntry {
+	return configmapmanager.DNSEntry{PodName: name, Network: network, Scope: r.Scope, Namespace: namespace, Cluster: r.Cluster}
+}
+
+// ownsRecord reports whether a registered name was generated for the given pod. Fields that the
+// naming template does not include, other than Namespace, cannot be compared and are ignored.
+func (r *PodReconciler) ownsRecord(nt *configmapmanager.NameTemplate, namespace, name, dnsName string) bool {
+	entry, err := nt.ParseKey(dnsName)
+	if err != nil || entry.PodName != name || entry.Namespace != namespace {
+		return false
+	}
+	if entry.Scope != "" && entry.Scope != r.Scope {
+		return false
+	}
+	return entry.Cluster == "" || entry.Cluster == r.Cluster
+}
+
+// diffPodRecords returns the ip -> []names records to add and to remove so that the records owned
+// by the pod match desired.
+func (r *PodReconciler) diffPodRecords(nt *configmapmanager.NameTemplate, namespace, name string, records, desired map[string][]string) (map[string][]string, map[string][]string) {
+	current := make(map[string]bool)
+	removals := make(map[string][]string)
+	for ip, names := range records {
+		for _, dnsName := range names {
+			if !r.ownsRecord(nt, namespace, name, dnsName) {
+				continue
+			}
+			current[ip+" "+dnsName] = true
+			if !contains(desired[ip], dnsName) {
+				removals[ip] = append(removals[ip], dnsName)
+			}
+		}
+	}
+
+	additions := make(map[string][]string)
+	for ip, names := range desired {
+		for _, dnsName := range names {
+			if !current[ip+" "+dnsName] {
+				additions[ip] = append(additions[ip], dnsName)
+			}
+		}
+	}
+	return additions, removals
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager registers the reconciler for pods that request, or used to request, L2SM networks.
+// It fails if the naming template does not include the Namespace field.
+func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if _, err := r.names(); err != nil {
+		return err
+	}
+	l2smPods := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return hasL2SMNetworks(e.Object.GetAnnotations()) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return hasL2SMNetworks(e.ObjectOld.GetAnnotations()) || hasL2SMNetworks(e.ObjectNew.GetAnnotations())
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return hasL2SMNetworks(e.Object.GetAnnotations()) },
+		GenericFunc: func(e event.GenericEvent) bool { return hasL2SMNetworks(e.Object.GetAnnotations()) },
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("l2sm-dns-pod").
+		For(&corev1.Pod{}).
+		WithEventFilter(l2smPods).
+		Complete(r)
+}
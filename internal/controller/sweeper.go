@@ -0,0 +1,195 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Sweeper periodically removes the DNS entries of pods that no longer exist, for example because
+// they died without a DeleteEntry call. An entry of this cluster is an orphan when no running pod
+// matches the namespace and pod name decoded from its name, or when the address it is registered
+// on no longer belongs to that pod; it is only removed once it has been an orphan for GracePeriod,
+// so that entries registered just before their pod shows up are kept.
+type Sweeper struct {
+	Client     client.Reader
+	DNSManager configmapmanager.DNSManager
+	// Interval between sweeps.
+	Interval time.Duration
+	// GracePeriod an entry must stay orphaned before it is removed.
+	GracePeriod time.Duration
+	// DryRun only reports the orphans that would be removed.
+	DryRun bool
+	// Cluster is the name of this cluster. Entries whose name encodes another cluster are never swept.
+	Cluster string
+	// Names decodes the DNS names of the entries, DefaultNameTemplate if nil. It must include the
+	// Namespace and Cluster fields: otherwise the entries of pods of other namespaces or of peer
+	// clusters could not be told apart from those of the pods of this cluster.
+	Names *configmapmanager.NameTemplate
+
+	mu sync.Mutex
+	// orphans maps "<ip> <name>" to the time the record was first seen orphaned.
+	orphans map[string]time.Time
+}
+
+// Start runs a sweep every Interval until ctx is done. It implements manager.Runnable, and fails at
+// once if the naming template lacks the Namespace or Cluster fields, or Cluster is not set.
+func (s *Sweeper) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("sweeper")
+	if _, err := s.names(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := s.Sweep(ctx); err != nil {
+				logger.Error(err, "could not sweep stale DNS entries")
+			}
+		}
+	}
+}
+
+// Sweep runs a single garbage collection pass and returns the ip -> []names records it removed,
+// or that it would have removed in dry-run mode.
+func (s *Sweeper) Sweep(ctx context.Context) (map[string][]string, error) {
+	logger := log.FromContext(ctx).WithName("sweeper")
+	nt, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.DNSManager.ListDNSRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list DNS records: %w", err)
+	}
+	pods := &corev1.PodList{}
+	if err := s.Client.List(ctx, pods); err != nil {
+		return nil, fmt.Errorf("could not list pods: %w", err)
+	}
+	// live maps "<namespace>/<name>" to the addresses of every running pod.
+	live := make(map[string]map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		live[pod.Namespace+"/"+pod.Name] = podAddresses(pod)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.orphans == nil {
+		s.orphans = make(map[string]time.Time)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	expired := make(map[string][]string)
+	for ip, names := range records {
+		for _, dnsName := range names {
+			if !s.isOrphan(nt, ip, dnsName, live) {
+				continue
+			}
+			key := ip + " " + dnsName
+			seen[key] = true
+			first, ok := s.orphans[key]
+			if !ok {
+				s.orphans[key] = now
+				first = now
+			}
+			if now.Sub(first) >= s.GracePeriod {
+				expired[ip] = append(expired[ip], dnsName)
+			}
+		}
+	}
+	// Forget records that were removed or whose pod came back.
+	for key := range s.orphans {
+		if !seen[key] {
+			delete(s.orphans, key)
+		}
+	}
+
+	if len(expired) == 0 {
+		return expired, nil
+	}
+	if s.DryRun {
+		logger.Info("found stale DNS entries (dry run)", "records", expired)
+		return expired, nil
+	}
+	if err := s.DNSManager.UpdateDNSRecords(ctx, nil, expired); err != nil {
+		return nil, fmt.Errorf("could not remove stale DNS entries: %w", err)
+	}
+	for ip, names := range expired {
+		for _, dnsName := range names {
+			delete(s.orphans, ip+" "+dnsName)
+		}
+	}
+	logger.Info("removed stale DNS entries", "records", expired)
+	return expired, nil
+}
+
+// names returns the naming template of the sweeper, or an error if it cannot tell the entries of
+// this cluster's pods apart.
+func (s *Sweeper) names() (*configmapmanager.NameTemplate, error) {
+	if s.Cluster == "" {
+		return nil, fmt.Errorf("the sweeper needs the name of this cluster")
+	}
+	names := s.Names
+	if names == nil {
+		var err error
+		if names, err = configmapmanager.DefaultNameTemplate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range []string{"Namespace", "Cluster"} {
+		if !slices.Contains(names.Fields(), field) {
+			return nil, fmt.Errorf("the sweeper needs a naming template that includes {{.%s}}, got one with fields %v", field, names.Fields())
+		}
+	}
+	return names, nil
+}
+
+// isOrphan reports whether the record of dnsName on ip is an L2SM entry of this cluster whose pod
+// is not running, or no longer has the address. Names that cannot be decoded, or that belong to
+// another cluster, are left alone. Pods that report no address yet keep all their entries.
+func (s *Sweeper) isOrphan(nt *configmapmanager.NameTemplate, ip, dnsName string, live map[string]map[string]bool) bool {
+	entry, err := nt.ParseKey(dnsName)
+	if err != nil || entry.PodName == "" || entry.Cluster != s.Cluster {
+		return false
+	}
+	addresses, running := live[entry.Namespace+"/"+entry.PodName]
+	if !running {
+		return true
+	}
+	return len(addresses) > 0 && !addresses[ip]
+}
+
+// NeedLeaderElection returns false: sweeps are idempotent and run in every replica.
+func (s *Sweeper) NeedLeaderElection() bool {
+	return false
+}
@@ -15,7 +15,14 @@
 package env
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 func getEnv(key, defaultValue string) string {
@@ -25,6 +32,74 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// invalid holds the error of every variable whose value could not be parsed, by name. The getters
+// fall back to the default value for them, and Validate reports them.
+var (
+	invalidMu sync.Mutex
+	invalid   = map[string]error{}
+)
+
+func lookupEnv[T any](key string, defaultValue T, parse func(string) (T, error)) T {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	v, err := parse(value)
+	invalidMu.Lock()
+	defer invalidMu.Unlock()
+	if err != nil {
+		invalid[key] = fmt.Errorf("invalid %s %q: %w", key, value, err)
+		return defaultValue
+	}
+	delete(invalid, key)
+	return v
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	return lookupEnv(key, defaultValue, time.ParseDuration)
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	return lookupEnv(key, defaultValue, strconv.ParseBool)
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	return lookupEnv(key, defaultValue, strconv.Atoi)
+}
+
+// Validate reads every variable that is not a plain string and returns an error naming those whose
+// value cannot be parsed, which the getters would otherwise silently replace with their default.
+func Validate() error {
+	GetBatchWindow()
+	GetBatchMaxSize()
+	GetPodControllerEnabled()
+	GetGCInterval()
+	GetGCGracePeriod()
+	GetGCDryRun()
+	GetLeaseCheckInterval()
+	GetReversePrefixV4()
+	GetReversePrefixV6()
+	GetRecordShards()
+	GetConfigMapMaxSize()
+	GetCRDStoreEnabled()
+	GetResponderEnabled()
+	GetResponderRefreshInterval()
+	GetPropagationInterval()
+
+	invalidMu.Lock()
+	defer invalidMu.Unlock()
+	keys := make([]string, 0, len(invalid))
+	for key := range invalid {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	errs := make([]error, 0, len(keys))
+	for _, key := range keys {
+		errs = append(errs, invalid[key])
+	}
+	return errors.Join(errs...)
+}
+
 func GetConfigMapNS() string {
 	return getEnv("CONFIGMAP_NS", "default")
 }
@@ -40,3 +115,160 @@ func GetServerPort() string {
 func GetInterDomainDomPort() string {
 	return getEnv("INTER_DOMAIN_DOM_PORT", ".:53")
 }
+
+// GetBatchWindow returns how long DNS entry mutations are coalesced before being written.
+// A zero window (the default) disables batching.
+func GetBatchWindow() time.Duration {
+	return getEnvDuration("DNS_BATCH_WINDOW", 0)
+}
+
+// GetBatchMaxSize returns how many mutations a batch may hold before it is written early.
+func GetBatchMaxSize() int {
+	return getEnvInt("DNS_BATCH_MAX_SIZE", 100)
+}
+
+// GetNameTemplate returns the Go template used to build DNS names from an entry's
+// PodName, Network, Scope, Namespace and Cluster fields.
+func GetNameTemplate() string {
+	return getEnv("DNS_NAME_TEMPLATE", "{{.PodName}}.{{.Network}}.{{.Scope}}")
+}
+
+// GetNameTLD returns the domain appended to every generated DNS name.
+func GetNameTLD() string {
+	return getEnv("DNS_TLD", "l2sm")
+}
+
+// GetPodControllerEnabled returns whether the server also runs the controller that registers
+// the L2SM network attachments of pods.
+func GetPodControllerEnabled() bool {
+	return getEnvBool("ENABLE_POD_CONTROLLER", false)
+}
+
+// GetPodControllerScope returns the scope of the entries registered by the pod controller.
+func GetPodControllerScope() string {
+	return getEnv("POD_CONTROLLER_SCOPE", "global")
+}
+
+// GetClusterName returns the cluster name used by naming templates that reference {{.Cluster}}.
+func GetClusterName() string {
+	return getEnv("CLUSTER_NAME", "")
+}
+
+// GetGCInterval returns the interval between sweeps of stale DNS entries. Zero disables the sweeper.
+func GetGCInterval() time.Duration {
+	return getEnvDuration("GC_INTERVAL", 0)
+}
+
+// GetGCGracePeriod returns how long an entry must stay orphaned before the sweeper removes it.
+func GetGCGracePeriod() time.Duration {
+	return getEnvDuration("GC_GRACE_PERIOD", 5*time.Minute)
+}
+
+// GetGCDryRun returns whether the sweeper only reports stale entries instead of removing them.
+func GetGCDryRun() bool {
+	return getEnvBool("GC_DRY_RUN", false)
+}
+
+// GetLeaseCheckInterval returns how often entries whose lease has lapsed are removed.
+func GetLeaseCheckInterval() time.Duration {
+	return getEnvDuration("LEASE_CHECK_INTERVAL", 30*time.Second)
+}
+
+// GetCoreDNSConfigDir returns the directory the CoreDNS ConfigMap is mounted on in the CoreDNS
+// container, where the files referenced by the Corefile are found.
+func GetCoreDNSConfigDir() string {
+	return getEnv("COREDNS_CONFIG_DIR", "/etc/coredns")
+}
+
+// GetReversePrefixV4 returns the prefix length of the IPv4 L2SM subnets, used to derive their
+// in-addr.arpa zones.
+func GetReversePrefixV4() int {
+	return getEnvInt("DNS_REVERSE_PREFIX_V4", 24)
+}
+
+// GetReversePrefixV6 returns the prefix length of the IPv6 L2SM subnets, used to derive their
+// ip6.arpa zones.
+func GetReversePrefixV6() int {
+	return getEnvInt("DNS_REVERSE_PREFIX_V6", 64)
+}
+
+// GetRecordStorage returns where the A/AAAA records are stored: "inline" in the Corefile, or
+// "hostsfile" in a separate key of the ConfigMap.
+func GetRecordStorage() string {
+	return getEnv("DNS_RECORD_STORAGE", "inline")
+}
+
+// GetRecordShards returns how many ConfigMaps the records are spread across in the "sharded" storage
+// mode.
+func GetRecordShards() int {
+	return getEnvInt("DNS_RECORD_SHARDS", 4)
+}
+
+// GetPodName returns the name of the pod the server runs in, set through the downward API. It is
+// used to check that the CoreDNS container of the pod mounts every record shard.
+func GetPodName() string {
+	return getEnv("POD_NAME", "")
+}
+
+// GetPodNamespace returns the namespace of the pod the server runs in, CONFIGMAP_NS if unset.
+func GetPodNamespace() string {
+	return getEnv("POD_NAMESPACE", GetConfigMapNS())
+}
+
+// GetConfigMapMaxSize returns the size in bytes above which a ConfigMap write is refused, before the
+// API server rejects it. It defaults to the 1 MiB limit of Kubernetes.
+func GetConfigMapMaxSize() int {
+	return getEnvInt("DNS_CONFIGMAP_MAX_SIZE", 1<<20)
+}
+
+// GetCRDStoreEnabled returns whether the DNS entries are stored as L2SMDNSEntry resources, from
+// which the Corefile is rendered.
+func GetCRDStoreEnabled() bool {
+	return getEnvBool("ENABLE_CRD_STORE", false)
+}
+
+// GetStorageBackend returns where the CoreDNS configuration is kept: "kubernetes" in a ConfigMap, or
+// "file" in the COREDNS_CONFIG_DIR directory, next to a standalone CoreDNS.
+func GetStorageBackend() string {
+	return getEnv("DNS_STORAGE_BACKEND", "kubernetes")
+}
+
+// GetResponderEnabled returns whether the server answers DNS queries for the L2SM zone itself.
+func GetResponderEnabled() bool {
+	return getEnvBool("ENABLE_DNS_RESPONDER", false)
+}
+
+// GetResponderAddr returns the address the DNS responder listens on, over UDP and TCP. It defaults to
+// port 1053, as the CoreDNS container of the same pod holds port 53.
+func GetResponderAddr() string {
+	return getEnv("DNS_RESPONDER_ADDR", ":1053")
+}
+
+// GetResponderUpstreams returns the comma-separated "host:port" servers the DNS responder forwards
+// the queries outside every known zone to. With none, such queries are refused.
+func GetResponderUpstreams() []string {
+	var upstreams []string
+	for _, upstream := range strings.Split(getEnv("DNS_RESPONDER_UPSTREAMS", ""), ",") {
+		if upstream = strings.TrimSpace(upstream); upstream != "" {
+			upstreams = append(upstreams, upstream)
+		}
+	}
+	return upstreams
+}
+
+// GetResponderRefreshInterval returns how often the DNS responder re-reads the CNAME, SRV and TXT
+// records and the forward servers.
+func GetResponderRefreshInterval() time.Duration {
+	return getEnvDuration("DNS_RESPONDER_REFRESH", 10*time.Second)
+}
+
+// GetPropagationEndpoint returns the "host:port" of the CoreDNS server that AddEntry queries to wait
+// for a new entry to resolve, by default the CoreDNS container next to the server.
+func GetPropagationEndpoint() string {
+	return getEnv("DNS_PROPAGATION_ENDPOINT", "127.0.0.1:53")
+}
+
+// GetPropagationInterval returns how often AddEntry queries the propagation endpoint while waiting.
+func GetPropagationInterval() time.Duration {
+	return getEnvDuration("DNS_PROPAGATION_INTERVAL", 500*time.Millisecond)
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// flushTimeout bounds how long a batch may take to be written to the ConfigMap.
+const flushTimeout = 30 * time.Second
+
+// batchingDNSManager wraps a DNSManager and coalesces the entry mutations received within a short
+// window, AddDNSEntry, AddDNSEntryWithLease, RenewDNSLease, RemoveDNSEntry and UpdateDNSRecords
+// alike, into a single ApplyEntryChanges call, and so into a single ConfigMap update. The changes
+// are applied in the order they were received, so a queued change never lands after a later one.
+// Every other method is passed through to the wrapped manager.
+type batchingDNSManager struct {
+	DNSManager
+	window   time.Duration
+	maxBatch int
+
+	mu sync.Mutex
+	// pending is the batch being filled, and size the number of changes it holds.
+	pending []*pendingCall
+	size    int
+	timer   *time.Timer
+	// ready holds the batches detached from pending and not written yet, oldest first.
+	ready [][]*pendingCall
+
+	// writeMu is held while batches are written, so that they are written one at a time and in order.
+	writeMu sync.Mutex
+}
+
+// pendingCall holds the changes of a single call, together with the channel its caller waits on.
+type pendingCall struct {
+	changes []EntryChange
+	done    chan callResult
+}
+
+type callResult struct {
+	results []ChangeResult
+	err     error
+}
+
+// NewBatchingDNSManager returns a DNSManager that batches entry mutations on top of inner.
+// A batch is written once window has elapsed since its first mutation, or as soon as it holds
+// maxBatch mutations. Each caller blocks until its batch is written and gets the result of its own
+// changes.
+func NewBatchingDNSManager(inner DNSManager, window time.Duration, maxBatch int) DNSManager {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+	return &batchingDNSManager{
+		DNSManager: inner,
+		window:     window,
+		maxBatch:   maxBatch,
+	}
+}
+
+func (b *batchingDNSManager) AddDNSEntry(ctx context.Context, dnsName string, ipAddresses ...string) error {
+	return applyOne(ctx, b, EntryChange{Op: EntryAdd, DNSName: dnsName, IPAddresses: ipAddresses})
+}
+
+func (b *batchingDNSManager) AddDNSEntryWithLease(ctx context.Context, dnsName string, ttl time.Duration, ipAddresses ...string) error {
+	return applyOne(ctx, b, EntryChange{Op: EntryAddWithLease, DNSName: dnsName, IPAddresses: ipAddresses, TTL: ttl})
+}
+
+func (b *batchingDNSManager) RenewDNSLease(ctx context.Context, dnsName string, ttl time.Duration) error {
+	return applyOne(ctx, b, EntryChange{Op: EntryRenew, DNSName: dnsName, TTL: ttl})
+}
+
+func (b *batchingDNSManager) RemoveDNSEntry(ctx context.Context, key string, ipAddresses ...string) error {
+	return applyOne(ctx, b, EntryChange{Op: EntryRemove, DNSName: key, IPAddresses: ipAddresses})
+}
+
+// UpdateDNSRecords queues the removals and then the additions, which are written in the same batch.
+// Nothing is queued if any address is invalid.
+func (b *batchingDNSManager) UpdateDNSRecords(ctx context.Context, additions, removals map[string][]string) error {
+	changes := recordChanges(additions, removals)
+	for _, change := range changes {
+		if _, err := change.normalize(); err != nil {
+			return err
+		}
+	}
+	results, err := b.ApplyEntryChanges(ctx, changes)
+	if err != nil {
+		return err
+	}
+	return firstError(results)
+}
+
+func (b *batchingDNSManager) RemoveDNSRecords(ctx context.Context, removals map[string][]string) error {
+	return b.UpdateDNSRecords(ctx, nil, removals)
+}
+
+func (b *batchingDNSManager) AddDNSEntryToConfigMap(ctx context.Context, updatedData map[string]string) error {
+	additions := make(map[string][]string, len(updatedData))
+	for ip, dnsName := range updatedData {
+		additions[ip] = append(additions[ip], dnsName)
+	}
+	return b.UpdateDNSRecords(ctx, additions, nil)
+}
+
+// ApplyEntryChanges queues the changes and waits for their batch to be written. Invalid changes are
+// reported right away and never queued.
+func (b *batchingDNSManager) ApplyEntryChanges(ctx context.Context, changes []EntryChange) ([]ChangeResult, error) {
+	results := make([]ChangeResult, len(changes))
+	var queued []EntryChange
+	var positions []int
+	for i, change := range changes {
+		normalized, err := change.normalize()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		queued = append(queued, normalized)
+		positions = append(positions, i)
+	}
+	if len(queued) == 0 {
+		return results, nil
+	}
+
+	queuedResults, err := b.enqueue(ctx, queued)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range positions {
+		results[i] = queuedResults[j]
+	}
+	return results, nil
+}
+
+// ExpireDNSLeases writes the queued changes first, so that a lease added or renewed before the call
+// is taken into account.
+func (b *batchingDNSManager) ExpireDNSLeases(ctx context.Context, now time.Time) (map[string][]string, error) {
+	b.mu.Lock()
+	b.detachLocked()
+	b.mu.Unlock()
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.writeReadyLocked()
+	return b.DNSManager.ExpireDNSLeases(ctx, now)
+}
+
+// enqueue adds the changes to the current batch and waits for the batch to be written.
+// If ctx is done first, enqueue returns early, but the changes may still be applied.
+func (b *batchingDNSManager) enqueue(ctx context.Context, changes []EntryChange) ([]ChangeResult, error) {
+	call := &pendingCall{changes: changes, done: make(chan callResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	b.size += len(changes)
+	if b.size >= b.maxBatch {
+		b.detachLocked()
+		b.mu.Unlock()
+		go b.writeReady()
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, b.flushPending)
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case result := <-call.done:
+		return result.results, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detachLocked moves the current batch, if any, to the batches ready to be written. b.mu must be
+// held.
+func (b *batchingDNSManager) detachLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+	b.ready = append(b.ready, b.pending)
+	b.pending = nil
+	b.size = 0
+}
+
+func (b *batchingDNSManager) flushPending() {
+	b.mu.Lock()
+	b.detachLocked()
+	b.mu.Unlock()
+	b.writeReady()
+}
+
+// writeReady writes every batch ready to be written, oldest first.
+func (b *batchingDNSManager) writeReady() {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.writeReadyLocked()
+}
+
+// writeReadyLocked is writeReady for callers that hold b.writeMu.
+func (b *batchingDNSManager) writeReadyLocked() {
+	b.mu.Lock()
+	batches := b.ready
+	b.ready = nil
+	b.mu.Unlock()
+	for _, batch := range batches {
+		b.write(batch)
+	}
+}
+
+// write applies the changes of every call in the batch, in order, with a single ApplyEntryChanges
+// call, and reports to every caller the results of its own changes.
+func (b *batchingDNSManager) write(batch []*pendingCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	b.writeCalls(ctx, batch)
+}
+
+// writeCalls writes the calls with a single ApplyEntryChanges call. The changes were validated when
+// they were queued, and a change that fails on its own, e.g. the renewal of a name that is not
+// registered, only fails its call. If the write fails as a whole because of what the calls hold, e.g.
+// a call that takes the ConfigMap over its size limit, the calls are split in two halves written one
+// after the other, until only the calls at fault get the error.
+func (b *batchingDNSManager) writeCalls(ctx context.Context, calls []*pendingCall) {
+	var changes []EntryChange
+	for _, call := range calls {
+		changes = append(changes, call.changes...)
+	}
+
+	results, err := b.DNSManager.ApplyEntryChanges(ctx, changes)
+	if err != nil && len(calls) > 1 && splitOnError(err) {
+		half := len(calls) / 2
+		b.writeCalls(ctx, calls[:half])
+		b.writeCalls(ctx, calls[half:])
+		return
+	}
+	for _, call := range calls {
+		if err != nil {
+			call.done <- callResult{err: err}
+			continue
+		}
+		call.done <- callResult{results: results[:len(call.changes)]}
+		results = results[len(call.changes):]
+	}
+}
+
+// splitOnError reports whether a batch whose write failed with err may succeed without some of its
+// calls. Conflicts and unreachable storage are not caused by the changes, and fail every call.
+func splitOnError(err error) bool {
+	switch ErrorKind(err) {
+	case ErrConflict, ErrUnavailable:
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
@@ -6,7 +6,9 @@ package configmapmanager
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -14,8 +16,12 @@ import (
 	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 )
 
 // DNSManager defines the interface for managing CoreDNS ConfigMaps.
@@ -23,16 +29,33 @@ type DNSManager interface {
 	GetConfigMap(ctx context.Context) (*v1.ConfigMap, error)
 	AddDNSEntryToConfigMap(ctx context.Context, updatedData map[string]string) error
 	RemoveDNSRecords(ctx context.Context, removals map[string][]string) error
+	UpdateDNSRecords(ctx context.Context, additions, removals map[string][]string) error
 	ListDNSRecords(ctx context.Context) (map[string][]string, error)
-	AddDNSEntry(ctx context.Context, dnsName, ipAddress string) error
-	RemoveDNSEntry(ctx context.Context, key, ipAddress string) error
+	AddDNSEntry(ctx context.Context, dnsName string, ipAddresses ...string) error
+	AddDNSEntryWithLease(ctx context.Context, dnsName string, ttl time.Duration, ipAddresses ...string) error
+	RenewDNSLease(ctx context.Context, dnsName string, ttl time.Duration) error
+	ExpireDNSLeases(ctx context.Context, now time.Time) (map[string][]string, error)
+	RemoveDNSEntry(ctx context.Context, key string, ipAddresses ...string) error
+	ApplyEntryChanges(ctx context.Context, changes []EntryChange) ([]ChangeResult, error)
+	AtomicEntryChanges() bool
 	AddServerToConfigMap(ctx context.Context, domainName, serverDomain, serverPort string) error
+	AddForwardServer(ctx context.Context, domainName string, cfg ForwardConfig) (ServerResult, error)
+	RemoveServerFromConfigMap(ctx context.Context, domainName string) error
+	ListServers(ctx context.Context) ([]ForwardServer, error)
+	AddRecord(ctx context.Context, record Record) error
+	RemoveRecord(ctx context.Context, record Record) error
+	ListRecords(ctx context.Context) ([]Record, error)
+	MigrateRecordStorage(ctx context.Context) error
+	WatchDNSRecords(ctx context.Context) (map[string][]string, string, <-chan RecordEvent, error)
+	ReplayDNSRecords(resourceVersion string, records map[string][]string) ([]RecordEvent, bool)
 }
 
 // ConfigMapClient is an abstraction over different ways to interact with a ConfigMap.
 type ConfigMapClient interface {
 	Get(ctx context.Context) (*v1.ConfigMap, error)
 	Update(ctx context.Context, cfg *v1.ConfigMap) error
+	Create(ctx context.Context, cfg *v1.ConfigMap) error
+	Watch(ctx context.Context, resourceVersion string) (watch.Interface, error)
 }
 
 // --- Controller-runtime based client ---
@@ -63,6 +86,22 @@ func (c *crConfigMapClient) Update(ctx context.Context, cfg *v1.ConfigMap) error
 	return c.client.Update(ctx, cfg)
 }
 
+func (c *crConfigMapClient) Create(ctx context.Context, cfg *v1.ConfigMap) error {
+	return c.client.Create(ctx, cfg)
+}
+
+func (c *crConfigMapClient) Watch(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	watchClient, ok := c.client.(client.WithWatch)
+	if !ok {
+		return nil, fmt.Errorf("controller-runtime client does not support watches")
+	}
+	return watchClient.Watch(ctx, &v1.ConfigMapList{},
+		client.InNamespace(c.namespace),
+		client.MatchingFields{"metadata.name": c.name},
+		&client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}},
+	)
+}
+
 // --- Clientset based client ---
 type clientsetConfigMapClient struct {
 	clientset *kubernetes.Clientset
@@ -70,52 +109,75 @@ type clientsetConfigMapClient struct {
 	name      string
 }
 
-func newClientsetConfigMapClient(namespace, name string, k8sConfig *rest.Config) (ConfigMapClient, error) {
-	clientset, err := kubernetes.NewForConfig(k8sConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-	}
+func newClientsetConfigMapClient(namespace, name string, clientset *kubernetes.Clientset) ConfigMapClient {
 	return &clientsetConfigMapClient{
 		clientset: clientset,
 		namespace: namespace,
 		name:      name,
-	}, nil
+	}
 }
 
 func (c *clientsetConfigMapClient) Get(ctx context.Context) (*v1.ConfigMap, error) {
 	return c.clientset.CoreV1().ConfigMaps(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
 }
 
+// Update writes cfg and sets its resourceVersion to that of the written ConfigMap, like the
+// controller-runtime client does.
 func (c *clientsetConfigMapClient) Update(ctx context.Context, cfg *v1.ConfigMap) error {
-	_, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Update(ctx, cfg, metav1.UpdateOptions{})
+	updated, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Update(ctx, cfg, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	cfg.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+func (c *clientsetConfigMapClient) Create(ctx context.Context, cfg *v1.ConfigMap) error {
+	_, err := c.clientset.CoreV1().ConfigMaps(c.namespace).Create(ctx, cfg, metav1.CreateOptions{})
 	return err
 }
 
+func (c *clientsetConfigMapClient) Watch(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	return c.clientset.CoreV1().ConfigMaps(c.namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", c.name).String(),
+		ResourceVersion: resourceVersion,
+	})
+}
+
 // --- CoreDNSManager Implementation ---
 type coreDNSManager struct {
 	cmClient  ConfigMapClient
 	namespace string
 	configMap string
+	// shardClient returns a client for another ConfigMap of the namespace, used for the record shards.
+	shardClient func(name string) ConfigMapClient
+	// history remembers the records of the versions seen by WatchDNSRecords, for ReplayDNSRecords.
+	history recordHistory
 }
 
 // NewDNSManager is the factory function that creates a DNSManager.
 // If crClient is provided (non-nil), it uses the controller-runtime client;
 // otherwise, it falls back to using the standard Kubernetes clientset.
 func NewDNSManager(namespace, configMap string, k8sConfig *rest.Config, crClient client.Client) (DNSManager, error) {
-	var cmClient ConfigMapClient
-	var err error
+	var newClient func(name string) ConfigMapClient
 	if crClient != nil {
-		cmClient = newCRConfigMapClient(namespace, configMap, crClient)
+		newClient = func(name string) ConfigMapClient {
+			return newCRConfigMapClient(namespace, name, crClient)
+		}
 	} else {
-		cmClient, err = newClientsetConfigMapClient(namespace, configMap, k8sConfig)
+		clientset, err := kubernetes.NewForConfig(k8sConfig)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+		}
+		newClient = func(name string) ConfigMapClient {
+			return newClientsetConfigMapClient(namespace, name, clientset)
 		}
 	}
 	return &coreDNSManager{
-		cmClient:  cmClient,
-		namespace: namespace,
-		configMap: configMap,
+		cmClient:    newClient(configMap),
+		namespace:   namespace,
+		configMap:   configMap,
+		shardClient: newClient,
 	}, nil
 }
 
@@ -124,88 +186,212 @@ func (m *coreDNSManager) GetConfigMap(ctx context.Context) (*v1.ConfigMap, error
 	return m.cmClient.Get(ctx)
 }
 
-func (m *coreDNSManager) AddDNSEntryToConfigMap(ctx context.Context, updatedData map[string]string) error {
-	cfg, err := m.GetConfigMap(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get ConfigMap: %w", err)
-	}
+// conflictBackoff bounds how many times a read-modify-write of the ConfigMap is retried
+// when another writer updated it in between, and how long to wait between attempts.
+var conflictBackoff = wait.Backoff{
+	Steps:    10,
+	Duration: 10 * time.Millisecond,
+	Factor:   1.5,
+	Jitter:   0.5,
+	Cap:      time.Second,
+}
 
-	coreFileString, ok := cfg.Data["Corefile"]
-	if !ok {
-		return fmt.Errorf("corefile not found in ConfigMap data")
-	}
+// updateCorefile fetches the ConfigMap, applies mutate to its parsed Corefile and writes the result back,
+// unless the mutation left the rendered Corefile unchanged.
+// If the update fails with a resourceVersion conflict, the mutation is re-applied on a freshly fetched
+// ConfigMap, so concurrent writers never overwrite each other's changes.
+func (m *coreDNSManager) updateCorefile(ctx context.Context, mutate func(cf *corefile.Corefile) error) error {
+	_, _, err := m.updateCorefileAndLeases(ctx, func(cf *corefile.Corefile, _ leaseTable) error {
+		return mutate(cf)
+	})
+	return err
+}
 
-	cf, err := corefile.New(coreFileString)
+// updateCorefileAndLeases is updateConfigMap for mutations that also edit the entry leases stored
+// next to the Corefile.
+func (m *coreDNSManager) updateCorefileAndLeases(ctx context.Context, mutate func(cf *corefile.Corefile, leases leaseTable) error) (map[string][]string, string, error) {
+	return m.updateConfigMap(ctx, func(cf *corefile.Corefile, data map[string]string) error {
+		leases, err := parseLeases(data[LeasesKey])
+		if err != nil {
+			return err
+		}
+		if err := mutate(cf, leases); err != nil {
+			return err
+		}
+		return storeLeases(data, leases)
+	})
+}
+
+// updateConfigMap is updateCorefile for mutations that also edit other keys of the ConfigMap data.
+// data holds every key but the Corefile. mutate always sees the records inline in the hosts plugin,
+// whatever the storage mode. Leases of names that are no longer registered are dropped, and the
+// hosts plugin is kept able to answer reverse lookups of every registered address.
+//
+// It returns the ip -> []names records and the resourceVersion of the ConfigMap as written, or as
+// read if nothing changed, so callers can report the state of their own write.
+func (m *coreDNSManager) updateConfigMap(ctx context.Context, mutate func(cf *corefile.Corefile, data map[string]string) error) (map[string][]string, string, error) {
+	var prune []string
+	var records map[string][]string
+	var resourceVersion string
+	err := retry.RetryOnConflict(conflictBackoff, func() error {
+		prune = nil
+		cfg, err := m.GetConfigMap(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+
+		coreFileString, ok := cfg.Data["Corefile"]
+		if !ok {
+			return failedPrecondition("corefile not found in ConfigMap data")
+		}
+
+		cf, err := corefile.New(coreFileString)
+		if err != nil {
+			return failedPrecondition("could not parse existing corefile: %v", err)
+		}
+
+		data := make(map[string]string, len(cfg.Data))
+		for key, value := range cfg.Data {
+			if key != "Corefile" {
+				data[key] = value
+			}
+		}
+
+		before := cf.ToString()
+		shards, err := m.loadShards(ctx, cf, data)
+		if err != nil {
+			return err
+		}
+		if err := loadHostsFile(cf, data); err != nil {
+			return err
+		}
+		if err := mutate(cf, data); err != nil {
+			return err
+		}
+		if err := pruneLeases(cf, data); err != nil {
+			return err
+		}
+		// The records are read before the storage mode moves them out of the hosts plugin.
+		if records, err = hostsRecords(cf); err != nil {
+			return err
+		}
+		if err := ensureReverseLookups(cf, data); err != nil {
+			return err
+		}
+		plan, err := m.storeShards(ctx, cf, data, shards)
+		if err != nil {
+			return err
+		}
+		if err := storeHostsFile(cf, data); err != nil {
+			return err
+		}
+
+		after := cf.ToString()
+		data["Corefile"] = after
+		if after == before && maps.Equal(data, cfg.Data) {
+			// Nothing changed: skip the write so the ConfigMap (and CoreDNS) are left untouched.
+			resourceVersion = cfg.ResourceVersion
+			return nil
+		}
+		cfg.Data = data
+		if err := checkConfigMapSize(cfg); err != nil {
+			return err
+		}
+		// The shards are written first, so the ConfigMap never references a zone file that is missing,
+		// and restored if the ConfigMap is not updated, e.g. on a conflict, before the next attempt.
+		if err := plan.write(ctx, m); err != nil {
+			plan.rollback(ctx, m)
+			return err
+		}
+		if err := m.cmClient.Update(ctx, cfg); err != nil {
+			plan.rollback(ctx, m)
+			return err
+		}
+		prune = plan.prune
+		resourceVersion = cfg.ResourceVersion
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("could not parse existing corefile: %v", err)
+		return nil, "", err
 	}
+	m.pruneShards(ctx, prune)
+	return records, resourceVersion, nil
+}
 
+// hostsRecords returns the ip -> []names records of the inter-domain hosts plugin of cf, or nil if
+// there is none.
+func hostsRecords(cf *corefile.Corefile) (map[string][]string, error) {
 	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
 	if !ok {
-		return fmt.Errorf("could not find inter-domain port '%v' in Corefile, check corefile syntax", env.GetInterDomainDomPort())
+		return nil, nil
 	}
-
 	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
 	if !ok {
-		return fmt.Errorf("could not find 'hosts' plugin in the inter-domain server block")
+		return nil, nil
 	}
+	return hostsPlugin.ListHostsEntries()
+}
 
+func (m *coreDNSManager) AddDNSEntryToConfigMap(ctx context.Context, updatedData map[string]string) error {
 	// Convert updatedData to map[ip][]domain
 	newEntries := map[string][]string{}
 	for ip, domain := range updatedData {
 		if net.ParseIP(ip) == nil {
-			return fmt.Errorf("invalid IP address in updatedData: %q", ip)
+			return invalidArgument("ip_address", "invalid IP address in updatedData: %q", ip)
 		}
 		newEntries[ip] = append(newEntries[ip], domain)
 	}
 
-	if err := hostsPlugin.AddHostsEntries(newEntries); err != nil {
-		return fmt.Errorf("failed to add host entries: %v", err)
-	}
-
-	cfg.Data["Corefile"] = cf.ToString()
-	return m.cmClient.Update(ctx, cfg)
+	_, _, err := m.updateHosts(ctx, func(hostsPlugin *corefile.Plugin, _ leaseTable) error {
+		if err := hostsPlugin.AddHostsEntries(newEntries); err != nil {
+			return fmt.Errorf("failed to add host entries: %v", err)
+		}
+		return nil
+	})
+	return err
 }
 
 func (m *coreDNSManager) RemoveDNSRecords(ctx context.Context, removals map[string][]string) error {
-	cfg, err := m.GetConfigMap(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get ConfigMap: %w", err)
-	}
-
-	coreFileString, ok := cfg.Data["Corefile"]
-	if !ok {
-		return fmt.Errorf("corefile not found in ConfigMap data")
-	}
-
-	cf, err := corefile.New(coreFileString)
-	if err != nil {
-		return fmt.Errorf("could not parse existing corefile: %v", err)
+	// Validate IPs
+	for ip := range removals {
+		if net.ParseIP(ip) == nil {
+			return invalidArgument("ip_address", "invalid IP address in removals: %q", ip)
+		}
 	}
 
-	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
-	if !ok {
-		return fmt.Errorf("could not find inter-domain server '%v' in Corefile", env.GetInterDomainDomPort())
-	}
+	_, _, err := m.updateHosts(ctx, func(hostsPlugin *corefile.Plugin, _ leaseTable) error {
+		if err := hostsPlugin.RemoveHostsEntries(removals); err != nil {
+			return fmt.Errorf("failed to remove host entries: %v", err)
+		}
+		return nil
+	})
+	return err
+}
 
-	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
-	if !ok {
-		return fmt.Errorf("could not find 'hosts' plugin in inter-domain server block")
+// UpdateDNSRecords removes and adds the given ip -> []domains records in a single Corefile update.
+// Removals are applied before additions.
+func (m *coreDNSManager) UpdateDNSRecords(ctx context.Context, additions, removals map[string][]string) error {
+	for ip := range additions {
+		if net.ParseIP(ip) == nil {
+			return invalidArgument("ip_address", "invalid IP address in additions: %q", ip)
+		}
 	}
-
-	// Validate IPs
 	for ip := range removals {
 		if net.ParseIP(ip) == nil {
-			return fmt.Errorf("invalid IP address in removals: %q", ip)
+			return invalidArgument("ip_address", "invalid IP address in removals: %q", ip)
 		}
 	}
 
-	if err := hostsPlugin.RemoveHostsEntries(removals); err != nil {
-		return fmt.Errorf("failed to remove host entries: %v", err)
-	}
-
-	cfg.Data["Corefile"] = cf.ToString()
-	return m.cmClient.Update(ctx, cfg)
+	_, _, err := m.updateHosts(ctx, func(hostsPlugin *corefile.Plugin, _ leaseTable) error {
+		if err := hostsPlugin.RemoveHostsEntries(removals); err != nil {
+			return fmt.Errorf("failed to remove host entries: %v", err)
+		}
+		if err := hostsPlugin.AddHostsEntries(additions); err != nil {
+			return fmt.Errorf("failed to add host entries: %v", err)
+		}
+		return nil
+	})
+	return err
 }
 
 func (m *coreDNSManager) ListDNSRecords(ctx context.Context) (map[string][]string, error) {
@@ -213,105 +399,128 @@ func (m *coreDNSManager) ListDNSRecords(ctx context.Context) (map[string][]strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
 	}
+	return m.listHostsRecords(ctx, cfg)
+}
 
+// listHostsRecords returns the ip -> []domains records of the inter-domain hosts plugin in cfg,
+// including the ones stored outside of the Corefile.
+func (m *coreDNSManager) listHostsRecords(ctx context.Context, cfg *v1.ConfigMap) (map[string][]string, error) {
 	coreFileString, ok := cfg.Data["Corefile"]
 	if !ok {
-		return nil, fmt.Errorf("corefile not found in ConfigMap data")
+		return nil, failedPrecondition("corefile not found in ConfigMap data")
 	}
 
 	cf, err := corefile.New(coreFileString)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse existing corefile: %v", err)
+		return nil, failedPrecondition("could not parse existing corefile: %v", err)
 	}
 
 	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
 	if !ok {
-		return nil, fmt.Errorf("could not find inter-domain server '%v' in Corefile", env.GetInterDomainDomPort())
+		return nil, failedPrecondition("could not find inter-domain server '%v' in Corefile", env.GetInterDomainDomPort())
 	}
 
 	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
 	if !ok {
-		return nil, fmt.Errorf("could not find 'hosts' plugin in inter-domain server block")
+		return nil, failedPrecondition("could not find 'hosts' plugin in inter-domain server block")
 	}
 
+	data := maps.Clone(cfg.Data)
+	if _, err := m.loadShards(ctx, cf, data); err != nil {
+		return nil, err
+	}
+	if err := loadHostsFile(cf, data); err != nil {
+		return nil, err
+	}
 	return hostsPlugin.ListHostsEntries()
 }
 
-func (m *coreDNSManager) AddDNSEntry(ctx context.Context, dnsName, ipAddress string) error {
-	updatedData := map[string]string{
-		ipAddress: dnsName,
-	}
-	return m.AddDNSEntryToConfigMap(ctx, updatedData)
+// AddDNSEntry registers dnsName for every given address, e.g. both the IPv4 and the IPv6 address
+// of a dual-stack pod, in a single Corefile update.
+func (m *coreDNSManager) AddDNSEntry(ctx context.Context, dnsName string, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryAdd, DNSName: dnsName, IPAddresses: ipAddresses})
 }
 
-func (m *coreDNSManager) RemoveDNSEntry(ctx context.Context, key, ipAddress string) error {
-
-	deletedEntries := make(map[string][]string)
-
-	deletedEntries[ipAddress] = []string{key}
-
-	cfg, err := m.GetConfigMap(ctx)
-	if err != nil {
-		return err
-	}
-	coreFileString, ok := cfg.Data["Corefile"]
-	if !ok {
-		return fmt.Errorf("corefile not found in ConfigMap data")
-	}
-
-	cf, err := corefile.New(coreFileString)
-	if err != nil {
-		return fmt.Errorf("could not parse existing corefile: %v", err)
-	}
+// RemoveDNSEntry unregisters key from every given address. If no address is given, key is removed
+// from every address it is registered on.
+func (m *coreDNSManager) RemoveDNSEntry(ctx context.Context, key string, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryRemove, DNSName: key, IPAddresses: ipAddresses})
+}
 
-	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
-	if !ok {
-		return fmt.Errorf("could not find inter-domain port '%v' in Corefile, check corefile syntax", env.GetInterDomainDomPort())
-	}
+// AddServerToConfigMap adds a server block for domainName that forwards its queries to a single
+// upstream, with the default forward options. See AddForwardServer.
+func (m *coreDNSManager) AddServerToConfigMap(ctx context.Context, domainName, serverDomain, serverPort string) error {
+	_, err := m.AddForwardServer(ctx, domainName, ForwardConfig{
+		Upstreams: []string{net.JoinHostPort(serverDomain, serverPort)},
+	})
+	return err
+}
 
-	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
-	if !ok {
-		return fmt.Errorf("could not find 'hosts' plugin in the inter-domain server block")
-	}
+// ForwardServer is a server block that forwards the queries for a peer domain to other DNS servers.
+// ServerDomain and ServerPort are those of the first upstream.
+type ForwardServer struct {
+	DomPort      string
+	ServerDomain string
+	ServerPort   string
+	Forward      ForwardConfig
+}
 
-	if err := hostsPlugin.RemoveHostsEntries(deletedEntries); err != nil {
-		return fmt.Errorf("failed to add host entries: %v", err)
+// RemoveServerFromConfigMap deletes the server block of domainName. The inter-domain server block
+// holds the L2SM entries and cannot be removed.
+func (m *coreDNSManager) RemoveServerFromConfigMap(ctx context.Context, domainName string) error {
+	if domainName == env.GetInterDomainDomPort() {
+		return invalidArgument("dom_port", "the inter-domain server '%v' cannot be removed", domainName)
 	}
 
-	cfg.Data["Corefile"] = cf.ToString()
-
-	return m.cmClient.Update(ctx, cfg)
+	return m.updateCorefile(ctx, func(cf *corefile.Corefile) error {
+		if err := cf.RemoveServer(domainName); err != nil {
+			return notFound("failed to remove server from corefile: %v", err)
+		}
+		return nil
+	})
 }
 
-func (m *coreDNSManager) AddServerToConfigMap(ctx context.Context, domainName, serverDomain, serverPort string) error {
+// ListServers returns the server blocks that forward queries to another DNS server, in the order they
+// are declared. The inter-domain server block is not included.
+func (m *coreDNSManager) ListServers(ctx context.Context) ([]ForwardServer, error) {
 	cfg, err := m.GetConfigMap(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get ConfigMap: %w", err)
+		return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
 	}
 
 	coreFileString, ok := cfg.Data["Corefile"]
 	if !ok {
-		return fmt.Errorf("corefile not found in ConfigMap data")
+		return nil, failedPrecondition("corefile not found in ConfigMap data")
 	}
 
 	cf, err := corefile.New(coreFileString)
 	if err != nil {
-		return fmt.Errorf("could not parse existing corefile: %v", err)
+		return nil, failedPrecondition("could not parse existing corefile: %v", err)
 	}
 
-	forwardPlugin := corefile.Plugin{
-		Name: "forward",
-		Args: []string{".", fmt.Sprintf("%s:%s", serverDomain, serverPort)},
-	}
-	newServer := corefile.Server{
-		DomPorts: []string{domainName},
-		Plugins:  []*corefile.Plugin{&forwardPlugin},
+	servers := []ForwardServer{}
+	for _, server := range cf.ListServers() {
+		if fs, ok := forwardServer(server); ok {
+			servers = append(servers, fs)
+		}
 	}
+	return servers, nil
+}
 
-	if err := cf.AddServer(newServer); err != nil {
-		return fmt.Errorf("failed to add server in corefile: %v", err)
+// forwardServer returns the forward server described by a server block, if it is one: it serves a
+// single domain other than the inter-domain one, with a forward plugin.
+func forwardServer(server *corefile.Server) (ForwardServer, bool) {
+	if len(server.DomPorts) != 1 || server.DomPorts[0] == env.GetInterDomainDomPort() {
+		return ForwardServer{}, false
 	}
-
-	cfg.Data["Corefile"] = cf.ToString()
-	return m.cmClient.Update(ctx, cfg)
+	forwardPlugin, ok := server.GetPlugin("forward")
+	if !ok || len(forwardPlugin.Args) < 2 {
+		return ForwardServer{}, false
+	}
+	fs := ForwardServer{DomPort: server.DomPorts[0], Forward: parseForwardPlugin(forwardPlugin)}
+	fs.ServerDomain = fs.Forward.Upstreams[0]
+	if host, port, err := net.SplitHostPort(fs.ServerDomain); err == nil {
+		fs.ServerDomain, fs.ServerPort = host, port
+	}
+	return fs, true
 }
@@ -0,0 +1,350 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance is the test suite every DNSManager implementation must pass, so that the
+// ConfigMap, file, in-memory and L2SMDNSEntry backends behave the same.
+package conformance
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns a new, empty DNSManager: its inter-domain server block (INTER_DOMAIN_DOM_PORT)
+// holds a hosts plugin without entries, and there is no other server block.
+type Factory func(t *testing.T) configmapmanager.DNSManager
+
+// Run runs every conformance test against the DNSManagers returned by newManager, each test with
+// a new one.
+func Run(t *testing.T, newManager Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, mgr configmapmanager.DNSManager)
+	}{
+		{"Entries", testEntries},
+		{"InvalidEntries", testInvalidEntries},
+		{"RemoveEntries", testRemoveEntries},
+		{"UpdateRecords", testUpdateRecords},
+		{"ApplyEntryChanges", testApplyEntryChanges},
+		{"Leases", testLeases},
+		{"Servers", testServers},
+		{"Records", testRecords},
+		{"Watch", testWatch},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, newManager(t))
+		})
+	}
+}
+
+func testEntries(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2", "fd00::2"))
+	// Adding an entry again is a no-op.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+		"fd00::2":  {"pod-b.net1.global.l2sm"},
+	}, records)
+}
+
+func testInvalidEntries(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+
+	require.Error(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "not-an-ip"))
+	require.Error(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+	require.Error(t, mgr.UpdateDNSRecords(ctx, map[string][]string{"not-an-ip": {"pod-a.net1.global.l2sm"}}, nil))
+	require.Error(t, mgr.RenewDNSLease(ctx, "missing.net1.global.l2sm", time.Minute))
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func testRemoveEntries(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "fd00::1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2", "fd00::2"))
+
+	// A single address.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm", "fd00::1"))
+	// Every address.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net1.global.l2sm"))
+	// Removing a missing entry is a no-op.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "missing.net1.global.l2sm"))
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+}
+
+func testUpdateRecords(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+
+	require.NoError(t, mgr.UpdateDNSRecords(ctx,
+		map[string][]string{"10.0.0.3": {"pod-a.net1.global.l2sm"}},
+		map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}},
+	))
+	require.NoError(t, mgr.RemoveDNSRecords(ctx, map[string][]string{"10.0.0.2": {"pod-b.net1.global.l2sm"}}))
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.3": {"pod-a.net1.global.l2sm"}}, records)
+}
+
+func testApplyEntryChanges(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "fd00::1"))
+
+	results, err := mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{
+		{Op: configmapmanager.EntryRemove, DNSName: "pod-a.net1.global.l2sm"},
+		{Op: configmapmanager.EntryAddWithLease, DNSName: "pod-a.net1.global.l2sm", IPAddresses: []string{"10.0.0.2"}, TTL: time.Minute},
+		{Op: configmapmanager.EntryRenew, DNSName: "missing.net1.global.l2sm", TTL: time.Minute},
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-b.net1.global.l2sm", IPAddresses: []string{"not-an-ip"}},
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-c.net1.global.l2sm", IPAddresses: []string{"10.0.0.3"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	// Failed changes are reported on their own, and do not prevent the others.
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+	require.ErrorIs(t, results[2].Err, configmapmanager.ErrNotFound)
+	require.ErrorIs(t, results[3].Err, configmapmanager.ErrInvalidArgument)
+	require.NoError(t, results[4].Err)
+	require.Equal(t, []bool{true, true, true}, []bool{results[0].Changed, results[1].Changed, results[4].Changed})
+
+	// Results report the addresses and resourceVersion of their name once every change was applied.
+	require.Equal(t, []string{"10.0.0.2"}, results[0].IPAddresses)
+	require.Equal(t, []string{"10.0.0.2"}, results[1].IPAddresses)
+	require.Equal(t, []string{"10.0.0.3"}, results[4].IPAddresses)
+	require.NotEmpty(t, results[1].ResourceVersion)
+	require.Equal(t, results[1].ResourceVersion, results[0].ResourceVersion)
+	require.NotEmpty(t, results[4].ResourceVersion)
+
+	// Changes that leave the records as they are report the current state, unchanged.
+	unchanged, err := mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-c.net1.global.l2sm", IPAddresses: []string{"10.0.0.3"}},
+		{Op: configmapmanager.EntryRemove, DNSName: "pod-c.net1.global.l2sm", IPAddresses: []string{"10.0.0.9"}},
+	})
+	require.NoError(t, err)
+	for _, result := range unchanged {
+		require.NoError(t, result.Err)
+		require.False(t, result.Changed)
+		require.Equal(t, []string{"10.0.0.3"}, result.IPAddresses)
+		require.Equal(t, results[4].ResourceVersion, result.ResourceVersion)
+	}
+
+	// A later change reports a newer resourceVersion.
+	later, err := mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-c.net1.global.l2sm", IPAddresses: []string{"10.0.0.4"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, later[0].Err)
+	requireNewer(t, results[4].ResourceVersion, later[0].ResourceVersion)
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-c.net1.global.l2sm", "10.0.0.4"))
+
+	// Changes are applied in order: the removal does not undo the later addition.
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.2": {"pod-a.net1.global.l2sm"},
+		"10.0.0.3": {"pod-c.net1.global.l2sm"},
+	}, records)
+
+	removed, err := mgr.ExpireDNSLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.2": {"pod-a.net1.global.l2sm"}}, removed)
+}
+
+func testLeases(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Minute, "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-b.net1.global.l2sm", time.Minute, "10.0.0.2"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-c.net1.global.l2sm", "10.0.0.3"))
+	require.NoError(t, mgr.RenewDNSLease(ctx, "pod-b.net1.global.l2sm", time.Hour))
+
+	// Nothing expired yet.
+	removed, err := mgr.ExpireDNSLeases(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	removed, err = mgr.ExpireDNSLeases(ctx, time.Now().Add(10*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, removed)
+
+	// Entries without a lease never expire.
+	removed, err = mgr.ExpireDNSLeases(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.2": {"pod-b.net1.global.l2sm"}}, removed)
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.3": {"pod-c.net1.global.l2sm"}}, records)
+}
+
+func testServers(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+
+	servers, err := mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, servers)
+
+	require.NoError(t, mgr.AddServerToConfigMap(ctx, "peer-a.org:53", "10.1.0.53", "53"))
+	peerB := configmapmanager.ForwardConfig{
+		Upstreams: []string{"10.2.0.53:53", "10.2.0.54:53"},
+		Policy:    configmapmanager.ForwardPolicySequential,
+	}
+	result, err := mgr.AddForwardServer(ctx, "peer-b.org:53", peerB)
+	require.NoError(t, err)
+	require.True(t, result.Changed)
+	require.Equal(t, configmapmanager.ForwardServer{DomPort: "peer-b.org:53", ServerDomain: "10.2.0.53", ServerPort: "53", Forward: peerB}, result.Server)
+	require.NotEmpty(t, result.ResourceVersion)
+
+	// Adding the same server again changes nothing.
+	again, err := mgr.AddForwardServer(ctx, "peer-b.org:53", peerB)
+	require.NoError(t, err)
+	require.False(t, again.Changed)
+	require.Equal(t, result.Server, again.Server)
+	require.Equal(t, result.ResourceVersion, again.ResourceVersion)
+
+	_, err = mgr.AddForwardServer(ctx, "peer-c.org:53", configmapmanager.ForwardConfig{})
+	require.Error(t, err)
+
+	servers, err = mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.ForwardServer{
+		{DomPort: "peer-a.org:53", ServerDomain: "10.1.0.53", ServerPort: "53", Forward: configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:53"}}},
+		{DomPort: "peer-b.org:53", ServerDomain: "10.2.0.53", ServerPort: "53", Forward: configmapmanager.ForwardConfig{
+			Upstreams: []string{"10.2.0.53:53", "10.2.0.54:53"},
+			Policy:    configmapmanager.ForwardPolicySequential,
+		}},
+	}, servers)
+
+	require.NoError(t, mgr.RemoveServerFromConfigMap(ctx, "peer-a.org:53"))
+	servers, err = mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	require.Equal(t, "peer-b.org:53", servers[0].DomPort)
+
+	// The entries survive server changes.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.RemoveServerFromConfigMap(ctx, "peer-b.org:53"))
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+}
+
+func testRecords(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx := context.Background()
+	cname := configmapmanager.Record{Name: "www.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, TTL: 60, Target: "pod-a.net1.global.l2sm"}
+	txt := configmapmanager.Record{Name: "pod-a.net1.global.l2sm", Type: configmapmanager.RecordTypeTXT, TTL: 60, Text: []string{"owner=team-a"}}
+
+	require.NoError(t, mgr.AddRecord(ctx, cname))
+	require.NoError(t, mgr.AddRecord(ctx, txt))
+	require.Error(t, mgr.AddRecord(ctx, configmapmanager.Record{Name: "www.example.org", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"}))
+
+	records, err := mgr.ListRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.Record{txt, cname}, records)
+
+	require.NoError(t, mgr.RemoveRecord(ctx, cname))
+	records, err = mgr.ListRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.Record{txt}, records)
+}
+
+func testWatch(t *testing.T, mgr configmapmanager.DNSManager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+
+	records, resourceVersion, events, err := mgr.WatchDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+	require.NotEmpty(t, resourceVersion)
+
+	// Events carry the resourceVersion the change that caused them reported.
+	results, err := mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-b.net1.global.l2sm", IPAddresses: []string{"10.0.0.2"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, configmapmanager.RecordEvent{
+		Type:            configmapmanager.RecordAdded,
+		IPAddress:       "10.0.0.2",
+		DNSName:         "pod-b.net1.global.l2sm",
+		ResourceVersion: results[0].ResourceVersion,
+	}, nextEvent(t, events))
+
+	results, err = mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{
+		{Op: configmapmanager.EntryAdd, DNSName: "pod-b.net1.global.l2sm", IPAddresses: []string{"10.0.0.4"}},
+	})
+	require.NoError(t, err)
+	event := nextEvent(t, events)
+	require.Equal(t, "10.0.0.4", event.IPAddress)
+	require.Equal(t, results[0].ResourceVersion, event.ResourceVersion)
+	requireNewer(t, resourceVersion, event.ResourceVersion)
+
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+	event = nextEvent(t, events)
+	require.Equal(t, configmapmanager.RecordDeleted, event.Type)
+	require.Equal(t, "10.0.0.1", event.IPAddress)
+	require.Equal(t, "pod-a.net1.global.l2sm", event.DNSName)
+
+	// The channel is closed once the context is done.
+	cancel()
+	for range events {
+	}
+}
+
+// requireNewer asserts that the resourceVersion newer was written after older. Every backend uses
+// increasing integers, although clients must treat them as opaque.
+func requireNewer(t *testing.T, older, newer string) {
+	t.Helper()
+	olderVersion, err := strconv.ParseUint(older, 10, 64)
+	require.NoError(t, err)
+	newerVersion, err := strconv.ParseUint(newer, 10, 64)
+	require.NoError(t, err)
+	require.Greater(t, newerVersion, olderVersion)
+}
+
+func nextEvent(t *testing.T, events <-chan configmapmanager.RecordEvent) configmapmanager.RecordEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "event channel closed")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a record event")
+		return configmapmanager.RecordEvent{}
+	}
+}
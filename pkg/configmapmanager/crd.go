@@ -0,0 +1,606 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1alpha1"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// crdDNSManager wraps a DNSManager and stores the DNS entries as L2SMDNSEntry resources instead of
+// writing them to the Corefile, which the EntryReconciler renders from the set of entries. Every
+// method that does not deal with entries is passed through to the wrapped manager.
+//
+// An entry is named after its DNS name, and lives in the namespace decoded from the name by the
+// naming template, or in the default namespace if the template has no namespace. An entry created
+// for a name that decodes to an existing pod is owned by it, so it is garbage collected with the pod.
+type crdDNSManager struct {
+	DNSManager
+	client    client.Client
+	namespace string
+	names     *NameTemplate
+}
+
+// NewCRDDNSManager returns a DNSManager that keeps the DNS entries as L2SMDNSEntry resources, in
+// namespace unless names decodes one from the name. c must know the v1alpha1 types. If names is nil,
+// the configured naming template is used.
+func NewCRDDNSManager(inner DNSManager, c client.Client, namespace string, names *NameTemplate) (DNSManager, error) {
+	if names == nil {
+		var err error
+		if names, err = DefaultNameTemplate(); err != nil {
+			return nil, err
+		}
+	}
+	return &crdDNSManager{
+		DNSManager: inner,
+		client:     c,
+		namespace:  namespace,
+		names:      names,
+	}, nil
+}
+
+// EntriesImportedAnnotation is set on the ConfigMap once its records were imported as L2SMDNSEntry
+// resources. Until then, the EntryReconciler does not remove the records that no entry backs, as
+// they were registered before the store was enabled.
+const EntriesImportedAnnotation = "dns.l2sm.k8s.local/entries-imported"
+
+// EntriesImported reports whether the records of cfg were imported as L2SMDNSEntry resources.
+func EntriesImported(cfg *corev1.ConfigMap) bool {
+	return cfg.Annotations[EntriesImportedAnnotation] == "true"
+}
+
+// MigrateRecordStorage migrates the records of the wrapped manager, then, the first time the store
+// is enabled, creates an L2SMDNSEntry for every name registered in the ConfigMap, with its lease,
+// and marks the ConfigMap with EntriesImportedAnnotation. Records written to the ConfigMap while
+// the store is disabled afterwards are not imported again.
+func (m *crdDNSManager) MigrateRecordStorage(ctx context.Context) error {
+	if err := m.DNSManager.MigrateRecordStorage(ctx); err != nil {
+		return err
+	}
+	cfg, err := m.DNSManager.GetConfigMap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+	if EntriesImported(cfg) {
+		return nil
+	}
+
+	records, err := m.DNSManager.ListDNSRecords(ctx)
+	if err != nil {
+		return err
+	}
+	leases, err := parseLeases(cfg.Data[LeasesKey])
+	if err != nil {
+		return err
+	}
+	byName := make(map[string][]string)
+	for ip, names := range records {
+		for _, name := range names {
+			byName[name] = append(byName[name], ip)
+		}
+	}
+	for _, name := range sortedKeys(byName) {
+		addresses := byName[name]
+		sort.Strings(addresses)
+		_, _, err := m.updateEntry(ctx, name, func(entry *v1alpha1.L2SMDNSEntry, exists bool) error {
+			addAddresses(entry, addresses)
+			if expiry, leased := leases[name]; leased && !exists {
+				entry.Spec.ExpiresAt = &metav1.Time{Time: expiry}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("could not import DNS entry %q: %w", name, err)
+		}
+	}
+
+	return retry.RetryOnConflict(conflictBackoff, func() error {
+		cfg, err := m.DNSManager.GetConfigMap(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+		if cfg.Annotations == nil {
+			cfg.Annotations = make(map[string]string)
+		}
+		cfg.Annotations[EntriesImportedAnnotation] = "true"
+		return m.client.Update(ctx, cfg)
+	})
+}
+
+// entryKey returns the key of the L2SMDNSEntry of dnsName.
+func (m *crdDNSManager) entryKey(dnsName string) (client.ObjectKey, error) {
+	name := strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return client.ObjectKey{}, invalidArgument("", "DNS name %q cannot be stored as an L2SMDNSEntry: %s", dnsName, strings.Join(errs, ", "))
+	}
+	namespace := m.namespace
+	if entry, err := m.names.ParseKey(name); err == nil && entry.Namespace != "" {
+		namespace = entry.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, nil
+}
+
+// updateEntry applies mutate to the L2SMDNSEntry of dnsName, which is a new object if exists is
+// false, and writes it back. An entry left without addresses is deleted. It returns the entry as
+// stored afterwards, nil if there is none, and whether mutate changed it.
+func (m *crdDNSManager) updateEntry(ctx context.Context, dnsName string, mutate func(entry *v1alpha1.L2SMDNSEntry, exists bool) error) (*v1alpha1.L2SMDNSEntry, bool, error) {
+	key, err := m.entryKey(dnsName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	var stored *v1alpha1.L2SMDNSEntry
+	var changed bool
+	err = retry.OnError(conflictBackoff, retriable, func() error {
+		stored, changed = nil, false
+		entry := &v1alpha1.L2SMDNSEntry{}
+		exists := true
+		if err := m.client.Get(ctx, key, entry); apierrors.IsNotFound(err) {
+			exists = false
+			entry = &v1alpha1.L2SMDNSEntry{
+				ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+				Spec:       v1alpha1.L2SMDNSEntrySpec{Hostname: dnsName},
+			}
+		} else if err != nil {
+			return fmt.Errorf("failed to get L2SMDNSEntry %s: %w", key, err)
+		}
+
+		before := entry.Spec.DeepCopy()
+		if err := mutate(entry, exists); err != nil {
+			return err
+		}
+		switch {
+		case len(entry.Spec.IPAddresses) == 0:
+			if !exists {
+				return nil
+			}
+			changed = true
+			return client.IgnoreNotFound(m.client.Delete(ctx, entry))
+		case !exists:
+			if err := m.setOwner(ctx, entry); err != nil {
+				return err
+			}
+			if err := m.client.Create(ctx, entry); err != nil {
+				return err
+			}
+		case equality.Semantic.DeepEqual(before, &entry.Spec):
+			stored = entry
+			return nil
+		default:
+			if err := m.client.Update(ctx, entry); err != nil {
+				return err
+			}
+		}
+		stored, changed = entry, true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, changed, nil
+}
+
+// setOwner makes the pod the name of entry decodes to, if it exists, the owner of entry.
+func (m *crdDNSManager) setOwner(ctx context.Context, entry *v1alpha1.L2SMDNSEntry) error {
+	dnsEntry, err := m.names.ParseKey(entry.Spec.Hostname)
+	if err != nil || dnsEntry.PodName == "" || dnsEntry.Namespace != entry.Namespace {
+		return nil
+	}
+	pod := &corev1.Pod{}
+	err = m.client.Get(ctx, client.ObjectKey{Namespace: entry.Namespace, Name: dnsEntry.PodName}, pod)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get owner pod of %q: %w", entry.Spec.Hostname, err)
+	}
+	entry.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}}
+	return nil
+}
+
+// addAddresses appends the addresses that entry does not have yet.
+func addAddresses(entry *v1alpha1.L2SMDNSEntry, addresses []string) {
+	for _, ip := range addresses {
+		if !slices.Contains(entry.Spec.IPAddresses, ip) {
+			entry.Spec.IPAddresses = append(entry.Spec.IPAddresses, ip)
+		}
+	}
+}
+
+// removeAddresses drops the given addresses from entry, or all of them if none is given.
+func removeAddresses(entry *v1alpha1.L2SMDNSEntry, addresses []string) {
+	if len(addresses) == 0 {
+		entry.Spec.IPAddresses = nil
+		return
+	}
+	entry.Spec.IPAddresses = slices.DeleteFunc(entry.Spec.IPAddresses, func(ip string) bool {
+		normalized, err := NormalizeIP(ip)
+		return err == nil && slices.Contains(addresses, normalized)
+	})
+}
+
+func (m *crdDNSManager) AddDNSEntry(ctx context.Context, dnsName string, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryAdd, DNSName: dnsName, IPAddresses: ipAddresses})
+}
+
+func (m *crdDNSManager) AddDNSEntryWithLease(ctx context.Context, dnsName string, ttl time.Duration, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryAddWithLease, DNSName: dnsName, IPAddresses: ipAddresses, TTL: ttl})
+}
+
+func (m *crdDNSManager) RenewDNSLease(ctx context.Context, dnsName string, ttl time.Duration) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryRenew, DNSName: dnsName, TTL: ttl})
+}
+
+func (m *crdDNSManager) RemoveDNSEntry(ctx context.Context, key string, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryRemove, DNSName: key, IPAddresses: ipAddresses})
+}
+
+// ApplyEntryChanges applies the changes in order. Unlike the ConfigMap-backed manager, every change
+// is written on its own, and a change whose write fails is reported in its result like an invalid one.
+// Like with the ConfigMap-backed manager, the addresses and resourceVersion of a result are those of
+// the entry once every change was applied.
+func (m *crdDNSManager) ApplyEntryChanges(ctx context.Context, changes []EntryChange) ([]ChangeResult, error) {
+	results := make([]ChangeResult, len(changes))
+	final := make(map[string]ChangeResult)
+	for i, change := range changes {
+		results[i] = m.applyEntryChange(ctx, change)
+		if results[i].Err == nil {
+			final[change.DNSName] = results[i]
+		}
+	}
+	for i, change := range changes {
+		if results[i].Err == nil {
+			results[i].IPAddresses = final[change.DNSName].IPAddresses
+			results[i].ResourceVersion = final[change.DNSName].ResourceVersion
+		}
+	}
+	return results, nil
+}
+
+// AtomicEntryChanges returns false: ApplyEntryChanges writes every change to its own L2SMDNSEntry,
+// so a failure partway through leaves the earlier changes written.
+func (m *crdDNSManager) AtomicEntryChanges() bool {
+	return false
+}
+
+// applyEntryChange writes a single change to the L2SMDNSEntry of its name.
+func (m *crdDNSManager) applyEntryChange(ctx context.Context, change EntryChange) ChangeResult {
+	change, err := change.normalize()
+	if err != nil {
+		return ChangeResult{Err: err}
+	}
+	entry, changed, err := m.updateEntry(ctx, change.DNSName, func(entry *v1alpha1.L2SMDNSEntry, exists bool) error {
+		switch change.Op {
+		case EntryAdd:
+			addAddresses(entry, change.IPAddresses)
+		case EntryAddWithLease:
+			addAddresses(entry, change.IPAddresses)
+			entry.Spec.ExpiresAt = nil
+			if change.TTL > 0 {
+				entry.Spec.ExpiresAt = &metav1.Time{Time: leaseExpiry(change.TTL)}
+			}
+		case EntryRemove:
+			removeAddresses(entry, change.IPAddresses)
+		case EntryRenew:
+			if !exists {
+				return notFound("DNS entry %q is not registered", change.DNSName)
+			}
+			entry.Spec.ExpiresAt = &metav1.Time{Time: leaseExpiry(change.TTL)}
+		}
+		return nil
+	})
+	if err != nil {
+		return ChangeResult{Err: err}
+	}
+	result := ChangeResult{Changed: changed}
+	if entry != nil {
+		for _, ip := range entry.Spec.IPAddresses {
+			if normalized, err := NormalizeIP(ip); err == nil {
+				result.IPAddresses = append(result.IPAddresses, normalized)
+			}
+		}
+		slices.SortFunc(result.IPAddresses, corefile.CompareIPs)
+		result.ResourceVersion = entry.ResourceVersion
+	}
+	return result
+}
+
+// UpdateDNSRecords applies the ip -> []names removals and additions entry by entry. Unlike the
+// ConfigMap-backed manager, the changes to different names are not written atomically.
+func (m *crdDNSManager) UpdateDNSRecords(ctx context.Context, additions, removals map[string][]string) error {
+	type change struct{ add, remove []string }
+	changes := make(map[string]*change)
+	get := func(name string) *change {
+		if changes[name] == nil {
+			changes[name] = &change{}
+		}
+		return changes[name]
+	}
+	for ip, names := range removals {
+		normalized, err := NormalizeIP(ip)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			get(name).remove = append(get(name).remove, normalized)
+		}
+	}
+	for ip, names := range additions {
+		normalized, err := NormalizeIP(ip)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			get(name).add = append(get(name).add, normalized)
+		}
+	}
+
+	for _, name := range sortedKeys(changes) {
+		c := changes[name]
+		_, _, err := m.updateEntry(ctx, name, func(entry *v1alpha1.L2SMDNSEntry, exists bool) error {
+			if exists && len(c.remove) > 0 {
+				removeAddresses(entry, c.remove)
+			}
+			addAddresses(entry, c.add)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *crdDNSManager) RemoveDNSRecords(ctx context.Context, removals map[string][]string) error {
+	return m.UpdateDNSRecords(ctx, nil, removals)
+}
+
+func (m *crdDNSManager) AddDNSEntryToConfigMap(ctx context.Context, updatedData map[string]string) error {
+	additions := make(map[string][]string, len(updatedData))
+	for ip, dnsName := range updatedData {
+		additions[ip] = append(additions[ip], dnsName)
+	}
+	return m.UpdateDNSRecords(ctx, additions, nil)
+}
+
+// ListDNSRecords returns the ip -> []names records of every L2SMDNSEntry, in every namespace.
+func (m *crdDNSManager) ListDNSRecords(ctx context.Context) (map[string][]string, error) {
+	entries := &v1alpha1.L2SMDNSEntryList{}
+	if err := m.client.List(ctx, entries); err != nil {
+		return nil, fmt.Errorf("failed to list L2SMDNSEntries: %w", err)
+	}
+	return EntryRecords(entries.Items), nil
+}
+
+// ReplayDNSRecords returns false: the versions of the L2SMDNSEntry resources are not remembered, so a
+// client resuming a watch always gets a snapshot.
+func (m *crdDNSManager) ReplayDNSRecords(resourceVersion string, records map[string][]string) ([]RecordEvent, bool) {
+	return nil, false
+}
+
+// WatchDNSRecords returns the ip -> []names records of every L2SMDNSEntry together with the
+// resourceVersion they were listed at, and a channel that receives every later change to them. Each
+// event carries the resourceVersion of the entry it comes from, the one ApplyEntryChanges reports.
+// The channel is closed once ctx is done. Dropped watches are re-established from a fresh list.
+func (m *crdDNSManager) WatchDNSRecords(ctx context.Context) (map[string][]string, string, <-chan RecordEvent, error) {
+	watchClient, ok := m.client.(client.WithWatch)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("controller-runtime client does not support watches")
+	}
+	entries, listVersion, resourceVersion, err := m.listEntries(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	// Open the first watch before returning, so no change made after this call can be missed.
+	w, err := watchEntryList(ctx, watchClient, listVersion)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	records := entryMapRecords(entries)
+	events := make(chan RecordEvent)
+	go m.watchEntries(ctx, watchClient, w, entries, events)
+	return records, resourceVersion, events, nil
+}
+
+// listEntries returns every L2SMDNSEntry by key, the resourceVersion of the list to watch from, and
+// the version the records were read at: that of the list, or, with clients that do not version
+// lists, that of the latest entry.
+func (m *crdDNSManager) listEntries(ctx context.Context) (map[client.ObjectKey]v1alpha1.L2SMDNSEntry, string, string, error) {
+	list := &v1alpha1.L2SMDNSEntryList{}
+	if err := m.client.List(ctx, list); err != nil {
+		return nil, "", "", fmt.Errorf("failed to list L2SMDNSEntries: %w", err)
+	}
+	entries := make(map[client.ObjectKey]v1alpha1.L2SMDNSEntry, len(list.Items))
+	resourceVersion := list.ResourceVersion
+	var latest uint64
+	for _, entry := range list.Items {
+		entries[client.ObjectKeyFromObject(&entry)] = entry
+		if version, err := strconv.ParseUint(entry.ResourceVersion, 10, 64); list.ResourceVersion == "" && err == nil && version > latest {
+			latest, resourceVersion = version, entry.ResourceVersion
+		}
+	}
+	return entries, list.ResourceVersion, resourceVersion, nil
+}
+
+func watchEntryList(ctx context.Context, c client.WithWatch, resourceVersion string) (watch.Interface, error) {
+	w, err := c.Watch(ctx, &v1alpha1.L2SMDNSEntryList{}, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch L2SMDNSEntries: %w", err)
+	}
+	return w, nil
+}
+
+func (m *crdDNSManager) watchEntries(ctx context.Context, c client.WithWatch, w watch.Interface, entries map[client.ObjectKey]v1alpha1.L2SMDNSEntry, events chan<- RecordEvent) {
+	defer close(events)
+
+	// publish diffs the records of entries against the last sent ones and sends the changes.
+	current := entryMapRecords(entries)
+	publish := func(resourceVersion string) bool {
+		records := entryMapRecords(entries)
+		for _, event := range diffRecords(current, records, resourceVersion) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		current = records
+		return true
+	}
+
+	for ctx.Err() == nil {
+		if w == nil {
+			listed, listVersion, resourceVersion, err := m.listEntries(ctx)
+			if err == nil {
+				w, err = watchEntryList(ctx, c, listVersion)
+			}
+			if err != nil {
+				log.Printf("could not watch L2SMDNSEntries, retrying in %v: %v", watchRetryInterval, err)
+				select {
+				case <-time.After(watchRetryInterval):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			entries = listed
+			if !publish(resourceVersion) {
+				w.Stop()
+				return
+			}
+		}
+
+		ok := consumeEntryWatch(ctx, w, entries, publish)
+		w.Stop()
+		w = nil
+		if !ok {
+			return
+		}
+	}
+}
+
+// consumeEntryWatch applies the events of w to entries and publishes them, until w is closed or
+// fails. It returns false once ctx is done.
+func consumeEntryWatch(ctx context.Context, w watch.Interface, entries map[client.ObjectKey]v1alpha1.L2SMDNSEntry, publish func(resourceVersion string) bool) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case ev, open := <-w.ResultChan():
+			if !open {
+				return true
+			}
+			entry, isEntry := ev.Object.(*v1alpha1.L2SMDNSEntry)
+			switch {
+			case ev.Type == watch.Error:
+				// Typically "resource version too old": start over from a fresh list.
+				return true
+			case !isEntry:
+				continue
+			case ev.Type == watch.Deleted:
+				delete(entries, client.ObjectKeyFromObject(entry))
+			case ev.Type == watch.Added, ev.Type == watch.Modified:
+				entries[client.ObjectKeyFromObject(entry)] = *entry
+			default:
+				continue
+			}
+			if !publish(entry.ResourceVersion) {
+				return false
+			}
+		}
+	}
+}
+
+// entryMapRecords returns the ip -> []names records of the entries.
+func entryMapRecords(entries map[client.ObjectKey]v1alpha1.L2SMDNSEntry) map[string][]string {
+	items := make([]v1alpha1.L2SMDNSEntry, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+	return EntryRecords(items)
+}
+
+// EntryRecords returns the ip -> []names records of entries. Invalid addresses are skipped.
+func EntryRecords(entries []v1alpha1.L2SMDNSEntry) map[string][]string {
+	records := make(map[string][]string)
+	for _, entry := range entries {
+		for _, ip := range entry.Spec.IPAddresses {
+			normalized, err := NormalizeIP(ip)
+			if err != nil || slices.Contains(records[normalized], entry.Spec.Hostname) {
+				continue
+			}
+			records[normalized] = append(records[normalized], entry.Spec.Hostname)
+		}
+	}
+	return records
+}
+
+// ExpireDNSLeases deletes every L2SMDNSEntry whose lease expired at or before now, and returns the
+// removed ip -> []names records.
+func (m *crdDNSManager) ExpireDNSLeases(ctx context.Context, now time.Time) (map[string][]string, error) {
+	entries := &v1alpha1.L2SMDNSEntryList{}
+	if err := m.client.List(ctx, entries); err != nil {
+		return nil, fmt.Errorf("failed to list L2SMDNSEntries: %w", err)
+	}
+	var expired []v1alpha1.L2SMDNSEntry
+	for _, entry := range entries.Items {
+		if entry.Spec.ExpiresAt == nil || entry.Spec.ExpiresAt.After(now) {
+			continue
+		}
+		// The precondition keeps an entry renewed in the meantime.
+		rv := entry.ResourceVersion
+		err := m.client.Delete(ctx, &entry, client.Preconditions{ResourceVersion: &rv})
+		if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete expired L2SMDNSEntry %s/%s: %w", entry.Namespace, entry.Name, err)
+		}
+		expired = append(expired, entry)
+	}
+	return EntryRecords(expired), nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+)
+
+// EntryOp is the kind of an EntryChange.
+type EntryOp int
+
+const (
+	// EntryAdd registers the name on the addresses, like AddDNSEntry. Its lease is left as is.
+	EntryAdd EntryOp = iota
+	// EntryAddWithLease registers the name on the addresses and sets its lease to TTL, like
+	// AddDNSEntryWithLease.
+	EntryAddWithLease
+	// EntryRemove unregisters the name from the addresses, or from every address if none is given,
+	// like RemoveDNSEntry.
+	EntryRemove
+	// EntryRenew sets the lease of a registered name to TTL, like RenewDNSLease.
+	EntryRenew
+)
+
+// EntryChange is a single mutation of the records of a DNS name, applied by ApplyEntryChanges.
+type EntryChange struct {
+	Op          EntryOp
+	DNSName     string
+	IPAddresses []string
+	// TTL of the lease, for EntryAddWithLease and EntryRenew.
+	TTL time.Duration
+}
+
+// ChangeResult is the outcome of an EntryChange.
+type ChangeResult struct {
+	// Err is the error of the change, which was then skipped without affecting the other changes.
+	// The other fields are only set if Err is nil.
+	Err error
+	// Changed is false if the change left the addresses and the lease of the name as they were.
+	Changed bool
+	// IPAddresses are the addresses the name is registered on once every change was applied, sorted
+	// by corefile.CompareIPs.
+	IPAddresses []string
+	// ResourceVersion is the resourceVersion of the object the name is stored in once every change
+	// was applied: the ConfigMap, or the L2SMDNSEntry of the name with the CRD store, "" if it was
+	// deleted.
+	ResourceVersion string
+}
+
+// normalize validates the change and returns it with its addresses in canonical form.
+func (c EntryChange) normalize() (EntryChange, error) {
+	addresses, err := NormalizeIPs(c.IPAddresses)
+	if err != nil {
+		return c, err
+	}
+	c.IPAddresses = addresses
+	switch c.Op {
+	case EntryAdd, EntryAddWithLease:
+		if len(addresses) == 0 {
+			return c, invalidArgument("ip_address", "at least one IP address is required for %q", c.DNSName)
+		}
+	case EntryRenew:
+		if c.TTL <= 0 {
+			return c, invalidArgument("ttl", "lease ttl must be positive, got %v", c.TTL)
+		}
+	case EntryRemove:
+	default:
+		return c, invalidArgument("", "unknown entry operation %d", c.Op)
+	}
+	return c, nil
+}
+
+// recordChanges returns the ip -> []names removals and additions as entry changes, the removals
+// first, so that applying them in order has the effect of UpdateDNSRecords.
+func recordChanges(additions, removals map[string][]string) []EntryChange {
+	var changes []EntryChange
+	for _, c := range []struct {
+		op      EntryOp
+		records map[string][]string
+	}{{EntryRemove, removals}, {EntryAdd, additions}} {
+		byName := make(map[string][]string)
+		for ip, names := range c.records {
+			for _, name := range names {
+				byName[name] = append(byName[name], ip)
+			}
+		}
+		for _, name := range sortedKeys(byName) {
+			addresses := byName[name]
+			sort.Strings(addresses)
+			changes = append(changes, EntryChange{Op: c.op, DNSName: name, IPAddresses: addresses})
+		}
+	}
+	return changes
+}
+
+// ApplyEntryChanges applies the changes in order, in a single Corefile update. A change that fails,
+// e.g. the renewal of a name that is not registered, is skipped and its error reported in its
+// result; the others are still written. The returned error is that of the update itself, in which
+// case no change was applied.
+func (m *coreDNSManager) ApplyEntryChanges(ctx context.Context, changes []EntryChange) ([]ChangeResult, error) {
+	normalized := make([]EntryChange, len(changes))
+	invalid := make([]error, len(changes))
+	valid := 0
+	for i, change := range changes {
+		normalized[i], invalid[i] = change.normalize()
+		if invalid[i] == nil {
+			valid++
+		}
+	}
+	if valid == 0 {
+		// Do not even read the ConfigMap, so that invalid changes are reported as such.
+		results := make([]ChangeResult, len(changes))
+		for i := range results {
+			results[i].Err = invalid[i]
+		}
+		return results, nil
+	}
+
+	var results []ChangeResult
+	records, resourceVersion, err := m.updateHosts(ctx, func(hostsPlugin *corefile.Plugin, leases leaseTable) error {
+		results = make([]ChangeResult, len(changes))
+		records, err := hostsPlugin.ListHostsEntries()
+		if err != nil {
+			return err
+		}
+		for i, change := range normalized {
+			if invalid[i] != nil {
+				results[i].Err = invalid[i]
+				continue
+			}
+			results[i].Changed, results[i].Err = applyEntryChange(records, leases, change)
+		}
+		return hostsPlugin.ReplaceHostsEntries(records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, change := range normalized {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].IPAddresses = addressesOf(records, change.DNSName)
+		slices.SortFunc(results[i].IPAddresses, corefile.CompareIPs)
+		results[i].ResourceVersion = resourceVersion
+	}
+	return results, nil
+}
+
+// AtomicEntryChanges reports whether ApplyEntryChanges writes every valid change of a call in a
+// single atomic update, which it does: they are all written with one ConfigMap update.
+func (m *coreDNSManager) AtomicEntryChanges() bool {
+	return true
+}
+
+// applyEntryChange applies a normalized change to the ip -> []names records and to the leases, and
+// reports whether it changed either.
+func applyEntryChange(records map[string][]string, leases leaseTable, change EntryChange) (bool, error) {
+	changed := false
+	switch change.Op {
+	case EntryAdd, EntryAddWithLease:
+		for _, ip := range change.IPAddresses {
+			if !slices.Contains(records[ip], change.DNSName) {
+				records[ip] = append(records[ip], change.DNSName)
+				changed = true
+			}
+		}
+		if change.Op == EntryAddWithLease {
+			_, leased := leases[change.DNSName]
+			if change.TTL > 0 {
+				leases[change.DNSName] = leaseExpiry(change.TTL)
+				changed = true
+			} else if leased {
+				delete(leases, change.DNSName)
+				changed = true
+			}
+		}
+	case EntryRemove:
+		addresses := change.IPAddresses
+		if len(addresses) == 0 {
+			addresses = addressesOf(records, change.DNSName)
+		}
+		for _, ip := range addresses {
+			if !slices.Contains(records[ip], change.DNSName) {
+				continue
+			}
+			records[ip] = removeNames(records[ip], []string{change.DNSName})
+			if len(records[ip]) == 0 {
+				delete(records, ip)
+			}
+			changed = true
+		}
+	case EntryRenew:
+		if len(addressesOf(records, change.DNSName)) == 0 {
+			return false, notFound("DNS entry %q is not registered", change.DNSName)
+		}
+		leases[change.DNSName] = leaseExpiry(change.TTL)
+		changed = true
+	}
+	return changed, nil
+}
+
+// applyOne applies a single change with m.ApplyEntryChanges and returns its error.
+func applyOne(ctx context.Context, m DNSManager, change EntryChange) error {
+	results, err := m.ApplyEntryChanges(ctx, []EntryChange{change})
+	if err != nil {
+		return err
+	}
+	return results[0].Err
+}
+
+// firstError returns the first error of the results, if any.
+func firstError(results []ChangeResult) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
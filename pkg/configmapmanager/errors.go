@@ -0,0 +1,102 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The kinds of the errors returned by a DNSManager, which they match with errors.Is. ErrorKind also
+// classifies the errors of the Kubernetes API. They are those of l2smzone, which the naming template
+// and the records return.
+var (
+	// ErrInvalidArgument is returned for invalid entries, addresses, records or servers.
+	ErrInvalidArgument = l2smzone.ErrInvalidArgument
+	// ErrNotFound is returned for entries, records, servers or ConfigMaps that do not exist.
+	ErrNotFound = l2smzone.ErrNotFound
+	// ErrAlreadyExists is returned when a record clashes with the ones that exist.
+	ErrAlreadyExists = l2smzone.ErrAlreadyExists
+	// ErrFailedPrecondition is returned when the CoreDNS configuration cannot take the change, e.g.
+	// it has no hosts plugin, until it is fixed.
+	ErrFailedPrecondition = l2smzone.ErrFailedPrecondition
+	// ErrConflict is returned when the ConfigMap was modified concurrently; the call can be retried.
+	ErrConflict = l2smzone.ErrConflict
+	// ErrUnavailable is returned when the storage cannot be reached; the call can be retried.
+	ErrUnavailable = l2smzone.ErrUnavailable
+)
+
+// Error is an error of a given kind. For ErrInvalidArgument, Field names the offending field.
+type Error = l2smzone.Error
+
+func newError(kind error, field string, format string, args ...any) error {
+	return &Error{Kind: kind, Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// invalidArgument returns an ErrInvalidArgument error about field.
+func invalidArgument(field string, format string, args ...any) error {
+	return newError(ErrInvalidArgument, field, format, args...)
+}
+
+func notFound(format string, args ...any) error {
+	return newError(ErrNotFound, "", format, args...)
+}
+
+func failedPrecondition(format string, args ...any) error {
+	return newError(ErrFailedPrecondition, "", format, args...)
+}
+
+// ErrorKind returns the kind of err, one of the Err* variables, or nil if it is not known.
+func ErrorKind(err error) error {
+	for _, kind := range []error{ErrInvalidArgument, ErrNotFound, ErrAlreadyExists, ErrFailedPrecondition, ErrConflict, ErrUnavailable} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case apierrors.IsConflict(err):
+		return ErrConflict
+	case apierrors.IsNotFound(err):
+		return ErrNotFound
+	case apierrors.IsAlreadyExists(err):
+		return ErrAlreadyExists
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err), apierrors.IsRequestEntityTooLargeError(err):
+		return ErrInvalidArgument
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return ErrFailedPrecondition
+	case apierrors.IsServerTimeout(err), apierrors.IsTimeout(err), apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsUnexpectedServerError(err):
+		return ErrUnavailable
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// ErrorField returns the field err is about, if any.
+func ErrorField(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Field
+	}
+	return ""
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// filePollInterval is how often a file-backed watch checks for new versions.
+var filePollInterval = time.Second
+
+// fileLockName is the file locked while a file-backed ConfigMap is read or written.
+const fileLockName = ".l2smdns.lock"
+
+// fileConfigMapClient is a ConfigMapClient that keeps a ConfigMap in a local directory, for
+// deployments next to a standalone CoreDNS. Every data key is a file of the directory, e.g. the
+// Corefile, so CoreDNS reads them as it would from a mounted ConfigMap. Several ConfigMaps may share
+// a directory, as long as their keys differ, like the record shards do.
+//
+// Files are written to a temporary file and renamed, so readers never see a partial file, and the
+// directory is locked so that concurrent writers, in this or other processes, do not interleave. A
+// state file per ConfigMap records which files belong to it, and a resourceVersion that emulates the
+// optimistic concurrency of the API server: an Update of an outdated object fails with a conflict.
+type fileConfigMapClient struct {
+	dir  string
+	name string
+}
+
+// fileState is the content of the state file of a file-backed ConfigMap.
+type fileState struct {
+	ResourceVersion int64    `json:"resourceVersion"`
+	Keys            []string `json:"keys"`
+}
+
+func newFileConfigMapClient(dir, name string) ConfigMapClient {
+	return &fileConfigMapClient{dir: dir, name: name}
+}
+
+// NewFileDNSManager returns a DNSManager that keeps the configMap ConfigMap in dir instead of in
+// Kubernetes: the Corefile is dir/Corefile, and every other data key is a file of dir too. An
+// existing Corefile is adopted as is.
+func NewFileDNSManager(dir, configMap string) (DNSManager, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Corefile directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid Corefile directory: %q is not a directory", dir)
+	}
+	return &coreDNSManager{
+		cmClient:  newFileConfigMapClient(dir, configMap),
+		configMap: configMap,
+		shardClient: func(name string) ConfigMapClient {
+			return newFileConfigMapClient(dir, name)
+		},
+	}, nil
+}
+
+func (c *fileConfigMapClient) statePath() string {
+	return filepath.Join(c.dir, ".l2smdns-"+c.name+".json")
+}
+
+func (c *fileConfigMapClient) notFound() error {
+	return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, c.name)
+}
+
+// readState returns the state of the ConfigMap, or nil if it does not exist. A directory without a
+// state file but with a Corefile holds a ConfigMap that was never written by the manager.
+func (c *fileConfigMapClient) readState() (*fileState, error) {
+	data, err := os.ReadFile(c.statePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(c.dir, "Corefile")); err == nil {
+			return &fileState{Keys: []string{"Corefile"}}, nil
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &fileState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", c.statePath(), err)
+	}
+	return state, nil
+}
+
+// read returns the ConfigMap. The caller holds the lock.
+func (c *fileConfigMapClient) read() (*v1.ConfigMap, error) {
+	state, err := c.readState()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, c.notFound()
+	}
+	cfg := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            c.name,
+			ResourceVersion: strconv.FormatInt(state.ResourceVersion, 10),
+		},
+		Data: make(map[string]string, len(state.Keys)),
+	}
+	for _, key := range state.Keys {
+		content, err := os.ReadFile(filepath.Join(c.dir, key))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Data[key] = string(content)
+	}
+	return cfg, nil
+}
+
+// write replaces the files of the ConfigMap with the data of cfg, and bumps its resourceVersion. The
+// caller holds the lock. Files are written so that CoreDNS, which may reload at any point, never
+// reads a Corefile that refers to a file not written yet: every other key first, then the Corefile,
+// then the state file, and the files of removed keys only once nothing refers to them.
+func (c *fileConfigMapClient) write(cfg *v1.ConfigMap, state *fileState) error {
+	keys := make([]string, 0, len(cfg.Data))
+	for key := range cfg.Data {
+		if key != filepath.Base(key) || key == "." || key == ".." || key == fileLockName {
+			return fmt.Errorf("invalid ConfigMap key %q", key)
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if key == "Corefile" {
+			continue
+		}
+		if err := writeFileAtomic(filepath.Join(c.dir, key), []byte(cfg.Data[key])); err != nil {
+			return err
+		}
+	}
+	if corefile, ok := cfg.Data["Corefile"]; ok {
+		if err := writeFileAtomic(filepath.Join(c.dir, "Corefile"), []byte(corefile)); err != nil {
+			return err
+		}
+	}
+
+	next := &fileState{ResourceVersion: 1, Keys: keys}
+	if state != nil {
+		next.ResourceVersion = state.ResourceVersion + 1
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.statePath(), data); err != nil {
+		return err
+	}
+
+	// The files of removed keys are deleted once the state no longer references them.
+	if state != nil {
+		for _, key := range state.Keys {
+			if _, kept := cfg.Data[key]; !kept {
+				if err := os.Remove(filepath.Join(c.dir, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+		}
+	}
+	cfg.ResourceVersion = strconv.FormatInt(next.ResourceVersion, 10)
+	return nil
+}
+
+func (c *fileConfigMapClient) Get(ctx context.Context) (*v1.ConfigMap, error) {
+	unlock, err := lockDir(c.dir, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return c.read()
+}
+
+func (c *fileConfigMapClient) Update(ctx context.Context, cfg *v1.ConfigMap) error {
+	unlock, err := lockDir(c.dir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := c.readState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return c.notFound()
+	}
+	if cfg.ResourceVersion != strconv.FormatInt(state.ResourceVersion, 10) {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, c.name,
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	return c.write(cfg, state)
+}
+
+func (c *fileConfigMapClient) Create(ctx context.Context, cfg *v1.ConfigMap) error {
+	unlock, err := lockDir(c.dir, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := c.readState()
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, c.name)
+	}
+	return c.write(cfg, nil)
+}
+
+// Watch polls the directory, and reports every version newer than resourceVersion as a Modified
+// event, or a Deleted event once the ConfigMap is gone.
+func (c *fileConfigMapClient) Watch(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	last, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil && resourceVersion != "" {
+		return nil, fmt.Errorf("invalid resourceVersion %q", resourceVersion)
+	}
+
+	events := make(chan watch.Event)
+	w := watch.NewProxyWatcher(events)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(filePollInterval)
+		defer ticker.Stop()
+		exists := true
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.StopChan():
+				return
+			case <-ticker.C:
+			}
+
+			event, ok := c.poll(&last, &exists)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			case <-w.StopChan():
+				return
+			}
+		}
+	}()
+	return w, nil
+}
+
+// poll returns the event to report for the current version of the ConfigMap, if any.
+func (c *fileConfigMapClient) poll(last *int64, exists *bool) (watch.Event, bool) {
+	cfg, err := c.Get(context.Background())
+	if apierrors.IsNotFound(err) {
+		if !*exists {
+			return watch.Event{}, false
+		}
+		*exists = false
+		return watch.Event{Type: watch.Deleted, Object: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: c.name}}}, true
+	}
+	if err != nil {
+		return watch.Event{Type: watch.Error, Object: &apierrors.NewInternalError(err).ErrStatus}, true
+	}
+	version, err := strconv.ParseInt(cfg.ResourceVersion, 10, 64)
+	if err != nil || (version <= *last && *exists) {
+		return watch.Event{}, false
+	}
+	*last, *exists = version, true
+	return watch.Event{Type: watch.Modified, Object: cfg}, true
+}
+
+// writeFileAtomic replaces path with data through a temporary file of the same directory, so that
+// readers see either the old or the new content.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
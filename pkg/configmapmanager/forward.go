@@ -0,0 +1,190 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+)
+
+// Forward policies supported by the CoreDNS forward plugin.
+const (
+	ForwardPolicyRandom     = "random"
+	ForwardPolicyRoundRobin = "round_robin"
+	ForwardPolicySequential = "sequential"
+)
+
+// tlsScheme prefixes the upstreams of the forward plugin that are reached over DNS-over-TLS.
+const tlsScheme = "tls://"
+
+// ForwardConfig is the configuration of the forward plugin of a server block. Zero values leave the
+// corresponding forward option unset, so CoreDNS applies its default.
+type ForwardConfig struct {
+	// Upstreams are the "host:port" addresses of the DNS servers queries are forwarded to.
+	Upstreams []string
+	// Policy is one of the ForwardPolicy* values.
+	Policy        string
+	HealthCheck   time.Duration
+	MaxFails      *int
+	Expire        time.Duration
+	TLS           bool
+	TLSServerName string
+}
+
+// Validate checks that the configuration can be rendered as a valid forward plugin.
+func (c ForwardConfig) Validate() error {
+	if len(c.Upstreams) == 0 {
+		return invalidArgument("upstreams", "at least one upstream server is required")
+	}
+	for _, upstream := range c.Upstreams {
+		host, port, err := net.SplitHostPort(upstream)
+		if err != nil || host == "" {
+			return invalidArgument("upstreams", "invalid upstream %q, expected host:port", upstream)
+		}
+		if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+			return invalidArgument("upstreams", "invalid port in upstream %q", upstream)
+		}
+	}
+	switch c.Policy {
+	case "", ForwardPolicyRandom, ForwardPolicyRoundRobin, ForwardPolicySequential:
+	default:
+		return invalidArgument("policy", "invalid forward policy %q", c.Policy)
+	}
+	if c.HealthCheck < 0 {
+		return invalidArgument("health_check", "health check interval must not be negative, got %v", c.HealthCheck)
+	}
+	if c.MaxFails != nil && *c.MaxFails < 0 {
+		return invalidArgument("max_fails", "max fails must not be negative, got %d", *c.MaxFails)
+	}
+	if c.Expire < 0 {
+		return invalidArgument("expire", "expire must not be negative, got %v", c.Expire)
+	}
+	if c.TLSServerName != "" && !c.TLS {
+		return invalidArgument("tls_servername", "tls server name %q requires TLS", c.TLSServerName)
+	}
+	return nil
+}
+
+// plugin renders the configuration as a forward plugin for every query (".").
+func (c ForwardConfig) plugin() *corefile.Plugin {
+	forwardPlugin := &corefile.Plugin{Name: "forward", Args: []string{"."}}
+	for _, upstream := range c.Upstreams {
+		if c.TLS {
+			upstream = tlsScheme + upstream
+		}
+		forwardPlugin.Args = append(forwardPlugin.Args, upstream)
+	}
+	addOption := func(name string, args ...string) {
+		forwardPlugin.Options = append(forwardPlugin.Options, &corefile.Option{Name: name, Args: args})
+	}
+	if c.Policy != "" {
+		addOption("policy", c.Policy)
+	}
+	if c.HealthCheck > 0 {
+		addOption("health_check", c.HealthCheck.String())
+	}
+	if c.MaxFails != nil {
+		addOption("max_fails", strconv.Itoa(*c.MaxFails))
+	}
+	if c.Expire > 0 {
+		addOption("expire", c.Expire.String())
+	}
+	if c.TLSServerName != "" {
+		addOption("tls_servername", c.TLSServerName)
+	}
+	return forwardPlugin
+}
+
+// parseForwardPlugin is the inverse of ForwardConfig.plugin. Options it does not model are ignored.
+func parseForwardPlugin(p *corefile.Plugin) ForwardConfig {
+	var c ForwardConfig
+	if len(p.Args) > 1 {
+		for _, upstream := range p.Args[1:] {
+			if strings.HasPrefix(upstream, tlsScheme) {
+				c.TLS = true
+				upstream = strings.TrimPrefix(upstream, tlsScheme)
+			}
+			c.Upstreams = append(c.Upstreams, upstream)
+		}
+	}
+	for _, opt := range p.Options {
+		if len(opt.Args) == 0 {
+			continue
+		}
+		switch opt.Name {
+		case "policy":
+			c.Policy = opt.Args[0]
+		case "health_check":
+			c.HealthCheck, _ = time.ParseDuration(opt.Args[0])
+		case "max_fails":
+			if n, err := strconv.Atoi(opt.Args[0]); err == nil {
+				c.MaxFails = &n
+			}
+		case "expire":
+			c.Expire, _ = time.ParseDuration(opt.Args[0])
+		case "tls_servername":
+			c.TLSServerName = opt.Args[0]
+		}
+	}
+	return c
+}
+
+// ServerResult is the outcome of AddForwardServer.
+type ServerResult struct {
+	// Server is the forward server of the domain as stored.
+	Server ForwardServer
+	// Changed is false if the server already existed with the same configuration.
+	Changed bool
+	// ResourceVersion is the resourceVersion of the ConfigMap after the call.
+	ResourceVersion string
+}
+
+// AddForwardServer adds a server block for domainName that forwards its queries as described by cfg.
+// If the server block already exists, its forward plugin is replaced.
+func (m *coreDNSManager) AddForwardServer(ctx context.Context, domainName string, cfg ForwardConfig) (ServerResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return ServerResult{}, err
+	}
+	newServer := corefile.Server{
+		DomPorts: []string{domainName},
+		Plugins:  []*corefile.Plugin{cfg.plugin()},
+	}
+
+	var result ServerResult
+	_, resourceVersion, err := m.updateCorefileAndLeases(ctx, func(cf *corefile.Corefile, _ leaseTable) error {
+		before := ""
+		if existing, ok := cf.GetServer(domainName); ok {
+			before = existing.ToString()
+		}
+		if err := cf.AddServer(newServer); err != nil {
+			return fmt.Errorf("failed to add server in corefile: %v", err)
+		}
+		stored, _ := cf.GetServer(domainName)
+		result.Server, _ = forwardServer(stored)
+		result.Changed = stored.ToString() != before
+		return nil
+	})
+	if err != nil {
+		return ServerResult{}, err
+	}
+	result.ResourceVersion = resourceVersion
+	return result, nil
+}
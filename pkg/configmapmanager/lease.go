@@ -0,0 +1,216 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+)
+
+// LeasesKey is the ConfigMap data key holding the expiry time of every DNS name registered with a
+// lease, as a JSON object mapping names to RFC 3339 timestamps. It is written in the same update as
+// the Corefile, so leases survive server restarts and never get out of sync with the records.
+const LeasesKey = "leases.json"
+
+// leaseTable maps DNS names to the time their lease expires.
+type leaseTable map[string]time.Time
+
+func parseLeases(data string) (leaseTable, error) {
+	leases := leaseTable{}
+	if data == "" {
+		return leases, nil
+	}
+	if err := json.Unmarshal([]byte(data), &leases); err != nil {
+		return nil, failedPrecondition("could not parse %s in ConfigMap data: %v", LeasesKey, err)
+	}
+	return leases, nil
+}
+
+// render returns the JSON form of the table, or "" if it is empty. Keys are sorted, so an
+// unchanged table always renders the same.
+func (l leaseTable) render() (string, error) {
+	if len(l) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", fmt.Errorf("could not render leases: %v", err)
+	}
+	return string(data), nil
+}
+
+// expiresBy reports whether any lease of the table expires at or before now.
+func (l leaseTable) expiresBy(now time.Time) bool {
+	for _, expiry := range l {
+		if !expiry.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// storeLeases writes the rendered leases to data, removing the key when there are none.
+func storeLeases(data map[string]string, leases leaseTable) error {
+	rendered, err := leases.render()
+	if err != nil {
+		return err
+	}
+	if rendered == "" {
+		delete(data, LeasesKey)
+	} else {
+		data[LeasesKey] = rendered
+	}
+	return nil
+}
+
+// pruneLeases drops the leases in data of names that are no longer registered in the Corefile.
+func pruneLeases(cf *corefile.Corefile, data map[string]string) error {
+	if data[LeasesKey] == "" {
+		return nil
+	}
+	leases, err := parseLeases(data[LeasesKey])
+	if err != nil {
+		return err
+	}
+	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+	if !ok {
+		return nil
+	}
+	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
+	if !ok {
+		return nil
+	}
+	records, err := hostsPlugin.ListHostsEntries()
+	if err != nil {
+		return err
+	}
+	pruneLeaseTable(leases, records)
+	return storeLeases(data, leases)
+}
+
+// pruneLeaseTable drops the leases of names that are not registered in the ip -> []names records.
+func pruneLeaseTable(leases leaseTable, records map[string][]string) {
+	registered := make(map[string]bool)
+	for _, names := range records {
+		for _, name := range names {
+			registered[name] = true
+		}
+	}
+	for name := range leases {
+		if !registered[name] {
+			delete(leases, name)
+		}
+	}
+}
+
+// AddDNSEntryWithLease registers dnsName like AddDNSEntry, and makes it expire after ttl unless the
+// lease is renewed. A ttl of zero or less removes any lease, so the entry never expires.
+func (m *coreDNSManager) AddDNSEntryWithLease(ctx context.Context, dnsName string, ttl time.Duration, ipAddresses ...string) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryAddWithLease, DNSName: dnsName, IPAddresses: ipAddresses, TTL: ttl})
+}
+
+// RenewDNSLease extends the lease of a registered dnsName so that it expires ttl from now. Entries
+// registered without a lease get one.
+func (m *coreDNSManager) RenewDNSLease(ctx context.Context, dnsName string, ttl time.Duration) error {
+	return applyOne(ctx, m, EntryChange{Op: EntryRenew, DNSName: dnsName, TTL: ttl})
+}
+
+// ExpireDNSLeases removes every name whose lease expired at or before now, from every address it is
+// registered on, and returns the removed ip -> []names records.
+func (m *coreDNSManager) ExpireDNSLeases(ctx context.Context, now time.Time) (map[string][]string, error) {
+	// Most calls have nothing to expire: check the leases before reading and writing every record.
+	cfg, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+	leases, err := parseLeases(cfg.Data[LeasesKey])
+	if err != nil {
+		return nil, err
+	}
+	if !leases.expiresBy(now) {
+		return map[string][]string{}, nil
+	}
+
+	var removed map[string][]string
+	_, _, err = m.updateHosts(ctx, func(hostsPlugin *corefile.Plugin, leases leaseTable) error {
+		removed = make(map[string][]string)
+		expired := make(map[string]bool)
+		for name, expiry := range leases {
+			if !expiry.After(now) {
+				expired[name] = true
+			}
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		records, err := hostsPlugin.ListHostsEntries()
+		if err != nil {
+			return err
+		}
+		for ip, names := range records {
+			for _, name := range names {
+				if expired[name] {
+					removed[ip] = append(removed[ip], name)
+				}
+			}
+		}
+		if err := hostsPlugin.RemoveHostsEntries(removed); err != nil {
+			return fmt.Errorf("failed to remove host entries: %v", err)
+		}
+		for name := range expired {
+			delete(leases, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// leaseExpiry returns the expiry time of a lease of the given ttl starting now. It is truncated to
+// whole seconds, the precision leases are stored with.
+func leaseExpiry(ttl time.Duration) time.Time {
+	return time.Now().Add(ttl).Truncate(time.Second).UTC()
+}
+
+// ExpireLeasesEvery calls m.ExpireDNSLeases every interval until ctx is done, logging the entries
+// it removes.
+func ExpireLeasesEvery(ctx context.Context, m DNSManager, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			removed, err := m.ExpireDNSLeases(ctx, now)
+			if err != nil {
+				log.Printf("could not expire DNS leases: %v", err)
+				continue
+			}
+			if len(removed) > 0 {
+				log.Printf("removed DNS entries with expired leases: %v", removed)
+			}
+		}
+	}
+}
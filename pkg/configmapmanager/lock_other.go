@@ -0,0 +1,34 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix
+
+package configmapmanager
+
+import "sync"
+
+// dirLock serializes the access to file-backed ConfigMaps where file locks are not available. It
+// only excludes writers of this process.
+var dirLock sync.RWMutex
+
+// lockDir takes the lock of the file-backed ConfigMaps, exclusive or shared, and returns the
+// function that releases it.
+func lockDir(dir string, exclusive bool) (func(), error) {
+	if exclusive {
+		dirLock.Lock()
+		return dirLock.Unlock, nil
+	}
+	dirLock.RLock()
+	return dirLock.RUnlock, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// memoryWatchQueue is how many events of an in-memory ConfigMap are buffered for a watch. A watch
+// that falls further behind is ended, like the API server does, so that writers never wait for it.
+const memoryWatchQueue = 100
+
+// memoryConfigMapClient is a ConfigMapClient that keeps a ConfigMap in memory, with the
+// resourceVersion checks and the watches of the API server.
+type memoryConfigMapClient struct {
+	name string
+
+	mu       sync.Mutex
+	cfg      *v1.ConfigMap
+	version  int64
+	watchers map[*memoryWatcher]struct{}
+}
+
+func newMemoryConfigMapClient(name string) *memoryConfigMapClient {
+	return &memoryConfigMapClient{
+		name:     name,
+		watchers: make(map[*memoryWatcher]struct{}),
+	}
+}
+
+// memoryWatcher is a watch of an in-memory ConfigMap.
+type memoryWatcher struct {
+	c      *memoryConfigMapClient
+	result chan watch.Event
+}
+
+func (w *memoryWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *memoryWatcher) Stop() {
+	w.c.mu.Lock()
+	defer w.c.mu.Unlock()
+	w.c.closeWatcher(w)
+}
+
+// closeWatcher ends w, unless it already ended. The caller holds the lock.
+func (c *memoryConfigMapClient) closeWatcher(w *memoryWatcher) {
+	if _, ok := c.watchers[w]; ok {
+		delete(c.watchers, w)
+		close(w.result)
+	}
+}
+
+// NewMemoryDNSManager returns a DNSManager that keeps its ConfigMaps in memory, for tests and for
+// embedding the manager without Kubernetes. It starts from corefile, or, if empty, from a Corefile
+// with an empty inter-domain hosts plugin. It behaves like the ConfigMap-backed manager in every
+// other respect, including its watches.
+func NewMemoryDNSManager(corefile string) DNSManager {
+	if corefile == "" {
+		corefile = fmt.Sprintf("%s {\n    hosts {\n    }\n}\n", env.GetInterDomainDomPort())
+	}
+
+	var mu sync.Mutex
+	clients := make(map[string]*memoryConfigMapClient)
+	newClient := func(name string) ConfigMapClient {
+		mu.Lock()
+		defer mu.Unlock()
+		if c, ok := clients[name]; ok {
+			return c
+		}
+		c := newMemoryConfigMapClient(name)
+		clients[name] = c
+		return c
+	}
+
+	configMap := env.GetConfigMapName()
+	cmClient := newClient(configMap).(*memoryConfigMapClient)
+	cmClient.cfg = &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMap, ResourceVersion: "1"},
+		Data:       map[string]string{"Corefile": corefile},
+	}
+	cmClient.version = 1
+	return &coreDNSManager{
+		cmClient:    cmClient,
+		configMap:   configMap,
+		shardClient: newClient,
+	}
+}
+
+func (c *memoryConfigMapClient) Get(ctx context.Context) (*v1.ConfigMap, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, c.name)
+	}
+	return c.cfg.DeepCopy(), nil
+}
+
+func (c *memoryConfigMapClient) Update(ctx context.Context, cfg *v1.ConfigMap) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg == nil {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, c.name)
+	}
+	if cfg.ResourceVersion != c.cfg.ResourceVersion {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, c.name,
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+	c.store(cfg, watch.Modified)
+	return nil
+}
+
+func (c *memoryConfigMapClient) Create(ctx context.Context, cfg *v1.ConfigMap) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg != nil {
+		return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, c.name)
+	}
+	c.store(cfg, watch.Added)
+	return nil
+}
+
+// store saves a copy of cfg with the next resourceVersion, and notifies the watches. The caller
+// holds the lock, so that the events are sent in order. The events are queued without blocking: a
+// watch whose queue is full is ended instead, and its client resumes it from the last version it
+// saw, which Watch reports first.
+func (c *memoryConfigMapClient) store(cfg *v1.ConfigMap, eventType watch.EventType) {
+	c.version++
+	cfg.ResourceVersion = strconv.FormatInt(c.version, 10)
+	c.cfg = cfg.DeepCopy()
+	for w := range c.watchers {
+		select {
+		case w.result <- watch.Event{Type: eventType, Object: c.cfg.DeepCopy()}:
+		default:
+			c.closeWatcher(w)
+		}
+	}
+}
+
+// Watch reports every change after resourceVersion. If the ConfigMap already changed since, the
+// current version is reported first.
+func (c *memoryConfigMapClient) Watch(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &memoryWatcher{c: c, result: make(chan watch.Event, memoryWatchQueue)}
+	if c.cfg != nil && c.cfg.ResourceVersion != resourceVersion {
+		w.result <- watch.Event{Type: watch.Modified, Object: c.cfg.DeepCopy()}
+	}
+	c.watchers[w] = struct{}{}
+	go func() {
+		<-ctx.Done()
+		w.Stop()
+	}()
+	return w, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+)
+
+// ReverseZone returns the in-addr.arpa or ip6.arpa zone of the subnet of ip, e.g. "0.0.10.in-addr.arpa."
+// for 10.0.0.1 with the default /24 IPv4 subnets. Prefix lengths are rounded down to a whole label:
+// octets for IPv4 and nibbles for IPv6.
+func ReverseZone(ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", invalidArgument("ip_address", "invalid IP address: %q", ip)
+	}
+
+	var labels []string
+	if v4 := parsed.To4(); v4 != nil {
+		prefix := clampPrefix(env.GetReversePrefixV4(), 32)
+		for i := 0; i < prefix/8; i++ {
+			labels = append(labels, fmt.Sprintf("%d", v4[i]))
+		}
+		return joinReverse(labels, "in-addr.arpa."), nil
+	}
+	prefix := clampPrefix(env.GetReversePrefixV6(), 128)
+	for i := 0; i < prefix/4; i++ {
+		b := parsed[i/2]
+		if i%2 == 0 {
+			b >>= 4
+		}
+		labels = append(labels, fmt.Sprintf("%x", b&0xf))
+	}
+	return joinReverse(labels, "ip6.arpa."), nil
+}
+
+func clampPrefix(prefix, bits int) int {
+	if prefix < 0 {
+		return 0
+	}
+	if prefix > bits {
+		return bits
+	}
+	return prefix
+}
+
+// joinReverse returns the labels in reverse order followed by suffix.
+func joinReverse(labels []string, suffix string) string {
+	reversed := make([]string, 0, len(labels)+1)
+	for i := len(labels) - 1; i >= 0; i-- {
+		reversed = append(reversed, labels[i])
+	}
+	return strings.Join(append(reversed, suffix), ".")
+}
+
+// ReverseZonesKey is the ConfigMap data key listing, one per line, the reverse zones that the
+// manager added to the hosts plugin of the inter-domain server block, so that they are removed once
+// no record needs them. Zones listed in the Corefile otherwise are never removed.
+const ReverseZonesKey = "l2sm.reverse-zones"
+
+// ensureReverseLookups makes the hosts plugin of the inter-domain server block answer PTR queries for
+// every registered address. The no_reverse option is not supported: it is removed on every write. If
+// the plugin is restricted to some zones, the reverse zone of every L2SM subnet is added to them, and
+// the zones it added before are removed once no record needs them, as recorded in ReverseZonesKey.
+func ensureReverseLookups(cf *corefile.Corefile, data map[string]string) error {
+	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+	if !ok {
+		return nil
+	}
+	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
+	if !ok {
+		return nil
+	}
+
+	options := hostsPlugin.Options[:0]
+	for _, opt := range hostsPlugin.Options {
+		if opt.Name == "no_reverse" {
+			log.Printf("removing no_reverse from the hosts plugin of %s: registered addresses always resolve in reverse", env.GetInterDomainDomPort())
+			continue
+		}
+		options = append(options, opt)
+	}
+	hostsPlugin.Options = options
+
+	// Without zones (hosts [FILE]), the plugin answers for every zone of the server block.
+	if len(hostsPlugin.Args) < 2 {
+		delete(data, ReverseZonesKey)
+		return nil
+	}
+	records, err := hostsPlugin.ListHostsEntries()
+	if err != nil {
+		return err
+	}
+	needed := make(map[string]bool)
+	for ip := range records {
+		zone, err := ReverseZone(ip)
+		if err != nil {
+			return err
+		}
+		needed[zone] = true
+	}
+	added := make(map[string]bool)
+	for _, zone := range strings.Fields(data[ReverseZonesKey]) {
+		added[zone] = true
+	}
+
+	args := hostsPlugin.Args[:1]
+	zones := make(map[string]bool)
+	var kept []string
+	for _, zone := range hostsPlugin.Args[1:] {
+		fqdn := strings.TrimSuffix(zone, ".") + "."
+		if added[fqdn] {
+			if !needed[fqdn] {
+				continue
+			}
+			kept = append(kept, fqdn)
+		}
+		zones[fqdn] = true
+		args = append(args, zone)
+	}
+	var missing []string
+	for zone := range needed {
+		if !zones[zone] {
+			missing = append(missing, zone)
+		}
+	}
+	sort.Strings(missing)
+	hostsPlugin.Args = append(args, missing...)
+
+	kept = append(kept, missing...)
+	sort.Strings(kept)
+	if len(kept) == 0 {
+		delete(data, ReverseZonesKey)
+	} else {
+		data[ReverseZonesKey] = strings.Join(kept, "\n") + "\n"
+	}
+	return nil
+}
@@ -0,0 +1,577 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"maps"
+	"net"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	"github.com/miekg/dns"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// ShardsKey is the ConfigMap data key indexing the zone files of the StorageSharded mode, as a JSON
+// object mapping every zone to the shard ConfigMap holding its zone file and to its serial.
+//
+// In that mode, the records of every name are stored in the zone of its parent domain, e.g.
+// "net1.global.l2sm" for "pod-a.net1.global.l2sm", which with the default naming template holds
+// the records of a network. The PTR records are stored in the zone of their reverse subnet. Zones
+// are spread across DNS_RECORD_SHARDS ConfigMaps by a hash of their name, and every zone file is
+// served by a file plugin of the inter-domain server block. Names directly under the TLD, or outside
+// it, stay in the hosts plugin.
+//
+// As every change to a zone bumps its serial, the index, and thus this ConfigMap, changes with the
+// shards, so watching it is enough to see every change to the records.
+const ShardsKey = "l2sm.shards"
+
+// shardEntry locates the zone file of a zone.
+type shardEntry struct {
+	ConfigMap string `json:"configMap"`
+	Serial    uint32 `json:"serial"`
+}
+
+// shardIndex maps zone names, without the trailing dot, to their zone file.
+type shardIndex map[string]shardEntry
+
+func parseShardIndex(data string) (shardIndex, error) {
+	index := shardIndex{}
+	if data == "" {
+		return index, nil
+	}
+	if err := json.Unmarshal([]byte(data), &index); err != nil {
+		return nil, failedPrecondition("could not parse %s in ConfigMap data: %v", ShardsKey, err)
+	}
+	return index, nil
+}
+
+// render returns the JSON form of the index, or "" if it is empty.
+func (idx shardIndex) render() (string, error) {
+	if len(idx) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return "", fmt.Errorf("could not render shard index: %v", err)
+	}
+	return string(data), nil
+}
+
+// shardKey returns the data key of the zone file of zoneName in its shard ConfigMap.
+func shardKey(zoneName string) string {
+	return "db." + zoneName
+}
+
+// shardFilePath returns the path of the zone file of zoneName in the CoreDNS container.
+func shardFilePath(zoneName string) string {
+	return path.Join(env.GetCoreDNSConfigDir(), shardKey(zoneName))
+}
+
+// isShardFilePlugin reports whether p serves the zone file of a shard.
+func isShardFilePlugin(p *corefile.Plugin) bool {
+	return p.Name == "file" && len(p.Args) > 0 && strings.HasPrefix(p.Args[0], shardFilePath(""))
+}
+
+// shardName returns the name of the shard ConfigMap the zone file of zoneName is stored in.
+func (m *coreDNSManager) shardName(zoneName string) string {
+	h := fnv.New32a()
+	h.Write([]byte(zoneName))
+	return ShardConfigMapName(m.configMap, int(h.Sum32()%uint32(recordShards())))
+}
+
+// recordShards returns DNS_RECORD_SHARDS, at least 1.
+func recordShards() int {
+	return max(env.GetRecordShards(), 1)
+}
+
+// ShardConfigMapName returns the name of the n-th shard ConfigMap of configMap.
+func ShardConfigMapName(configMap string, n int) string {
+	return fmt.Sprintf("%s-shard-%d", configMap, n)
+}
+
+// CheckShardVolumes checks that every volume of pod that mounts configMap also mounts its
+// DNS_RECORD_SHARDS shard ConfigMaps, without which CoreDNS cannot load the zone files of the
+// StorageSharded mode. The shards can only be listed one by one, so the manifest must be kept in
+// line with DNS_RECORD_SHARDS and with the final name of configMap, e.g. after a kustomize
+// namePrefix. It returns false if no volume of pod mounts configMap.
+func CheckShardVolumes(pod *v1.Pod, configMap string) (bool, error) {
+	mounted := false
+	for _, volume := range pod.Spec.Volumes {
+		var names []string
+		switch {
+		case volume.ConfigMap != nil:
+			names = append(names, volume.ConfigMap.Name)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					names = append(names, source.ConfigMap.Name)
+				}
+			}
+		}
+		if !slices.Contains(names, configMap) {
+			continue
+		}
+		mounted = true
+		var missing []string
+		for n := 0; n < recordShards(); n++ {
+			if name := ShardConfigMapName(configMap, n); !slices.Contains(names, name) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return true, failedPrecondition("volume %q of pod %s mounts ConfigMap %s but not its shards %v, one per DNS_RECORD_SHARDS=%d", volume.Name, pod.Name, configMap, missing, recordShards())
+		}
+	}
+	return mounted, nil
+}
+
+// shardZone returns the zone dnsName is stored in in the StorageSharded mode, or false if it stays
+// in the hosts plugin.
+func shardZone(dnsName string) (string, bool) {
+	name := dns.Fqdn(dnsName)
+	if _, ok := dns.IsDomainName(name); !ok || dns.CountLabel(name) < 2 {
+		return "", false
+	}
+	parent := name[dns.Split(name)[1]:]
+	if !dns.IsSubDomain(zoneOrigin(), parent) || dns.CountLabel(parent) <= dns.CountLabel(zoneOrigin()) {
+		return "", false
+	}
+	return strings.TrimSuffix(parent, "."), true
+}
+
+// shardSet holds the shard ConfigMaps and zones read during an update.
+type shardSet struct {
+	// configMaps by name. Shards that do not exist are nil.
+	configMaps map[string]*v1.ConfigMap
+	// zones by name, as read from the shards.
+	zones map[string]*zone
+}
+
+// getShard returns the shard ConfigMap name, or nil if it does not exist.
+func (m *coreDNSManager) getShard(ctx context.Context, shards *shardSet, name string) (*v1.ConfigMap, error) {
+	if cfg, ok := shards.configMaps[name]; ok {
+		return cfg, nil
+	}
+	cfg, err := m.shardClient(name).Get(ctx)
+	if apierrors.IsNotFound(err) {
+		cfg, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard ConfigMap %q: %w", name, err)
+	}
+	shards.configMaps[name] = cfg
+	return cfg, nil
+}
+
+// loadShards merges the A/AAAA records of the zone files indexed in the ShardsKey data key, if any,
+// into the inter-domain hosts plugin, so that they can be read and edited like inline records. The
+// PTR records, and the records copied from the ZoneKey zone, are derived from them and skipped.
+// storeShards moves them back according to the configured mode.
+func (m *coreDNSManager) loadShards(ctx context.Context, cf *corefile.Corefile, data map[string]string) (*shardSet, error) {
+	shards := &shardSet{configMaps: map[string]*v1.ConfigMap{}, zones: map[string]*zone{}}
+	index, err := parseShardIndex(data[ShardsKey])
+	if err != nil {
+		return nil, err
+	}
+	if len(index) == 0 {
+		return shards, nil
+	}
+	hostsPlugin, ok := interDomainHostsPlugin(cf)
+	if !ok {
+		return nil, failedPrecondition("could not find 'hosts' plugin in inter-domain server block")
+	}
+	records, err := hostsPlugin.ListHostsEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for zoneName, entry := range index {
+		cfg, err := m.getShard(ctx, shards, entry.ConfigMap)
+		if err != nil {
+			return nil, err
+		}
+		content, ok := "", false
+		if cfg != nil {
+			content, ok = cfg.Data[shardKey(zoneName)]
+		}
+		if !ok {
+			return nil, failedPrecondition("zone file of %q not found in shard ConfigMap %q", zoneName, entry.ConfigMap)
+		}
+		z, err := parseZoneFile(content, dns.Fqdn(zoneName), shardKey(zoneName))
+		if err != nil {
+			return nil, err
+		}
+		shards.zones[zoneName] = z
+
+		for _, rr := range z.records {
+			var ip string
+			switch rr := rr.(type) {
+			case *dns.A:
+				ip = rr.A.String()
+			case *dns.AAAA:
+				ip = rr.AAAA.String()
+			default:
+				continue
+			}
+			name := strings.TrimSuffix(rr.Header().Name, ".")
+			if !slices.Contains(records[ip], name) {
+				records[ip] = append(records[ip], name)
+			}
+		}
+	}
+	delete(data, ShardsKey)
+	return shards, hostsPlugin.ReplaceHostsEntries(records)
+}
+
+// shardPlan is the writes to the shard ConfigMaps that go with an update of the ConfigMap.
+type shardPlan struct {
+	// writes are the shard ConfigMaps to create or update before the ConfigMap. They keep the zone
+	// files that are no longer used, so the Corefile never references a missing file.
+	writes []*v1.ConfigMap
+	// previous holds the data of every shard of writes before the update, nil if it does not exist.
+	previous []map[string]string
+	// written is the number of writes done, which rollback undoes.
+	written int
+	// prune are the shard ConfigMaps to remove unused zone files from after the ConfigMap update.
+	prune []string
+}
+
+// storeShards lays the records of the inter-domain hosts plugin out according to the configured
+// storage mode: in StorageSharded mode the ones that can be sharded are moved to the zone files, and
+// the Corefile and the ShardsKey index are updated to reference them; otherwise they stay inline, and
+// the zone files are dropped. It returns the shard writes that go with the update.
+func (m *coreDNSManager) storeShards(ctx context.Context, cf *corefile.Corefile, data map[string]string, shards *shardSet) (*shardPlan, error) {
+	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+	if !ok {
+		return &shardPlan{}, nil
+	}
+	hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
+	if !ok {
+		return &shardPlan{}, nil
+	}
+
+	zones := make(map[string]*zone)
+	addRR := func(zoneName string, rr dns.RR) {
+		z, ok := zones[zoneName]
+		if !ok {
+			z = &zone{origin: dns.Fqdn(zoneName)}
+			zones[zoneName] = z
+		}
+		z.records = append(z.records, rr)
+	}
+	if env.GetRecordStorage() == StorageSharded {
+		records, err := hostsPlugin.ListHostsEntries()
+		if err != nil {
+			return nil, err
+		}
+		inline := make(map[string][]string)
+		for ip, names := range records {
+			for _, name := range names {
+				zoneName, ok := shardZone(name)
+				if !ok {
+					inline[ip] = append(inline[ip], name)
+					continue
+				}
+				address := net.ParseIP(ip)
+				if address == nil {
+					return nil, failedPrecondition("invalid IP address: %q", ip)
+				}
+				hdr := dns.RR_Header{Name: dns.Fqdn(name), Class: dns.ClassINET, Ttl: defaultRecordTTL}
+				if v4 := address.To4(); v4 != nil {
+					hdr.Rrtype = dns.TypeA
+					addRR(zoneName, &dns.A{Hdr: hdr, A: v4})
+				} else {
+					hdr.Rrtype = dns.TypeAAAA
+					addRR(zoneName, &dns.AAAA{Hdr: hdr, AAAA: address})
+				}
+
+				reverse, err := ReverseZone(ip)
+				if err != nil {
+					return nil, err
+				}
+				ptr, err := dns.ReverseAddr(ip)
+				if err != nil {
+					return nil, err
+				}
+				addRR(strings.TrimSuffix(reverse, "."), &dns.PTR{
+					Hdr: dns.RR_Header{Name: ptr, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: defaultRecordTTL},
+					Ptr: dns.Fqdn(name),
+				})
+			}
+		}
+		if err := hostsPlugin.ReplaceHostsEntries(inline); err != nil {
+			return nil, err
+		}
+
+		// The file plugin of a zone shadows the one of the ZoneKey zone, so the records of the latter
+		// under a sharded zone are copied into it.
+		mainZone, err := parseZone(data[ZoneKey])
+		if err != nil {
+			return nil, err
+		}
+		for _, rr := range mainZone.records {
+			for zoneName := range zones {
+				if dns.IsSubDomain(dns.Fqdn(zoneName), rr.Header().Name) {
+					addRR(zoneName, dns.Copy(rr))
+				}
+			}
+		}
+	}
+
+	// Keep the serial of unchanged zones, and bump the others so the file plugin reloads them.
+	index := shardIndex{}
+	for zoneName, z := range zones {
+		z.serial = 1
+		if old, ok := shards.zones[zoneName]; ok {
+			z.serial = old.serial
+			if z.render() != old.render() {
+				z.serial++
+			}
+		}
+		index[zoneName] = shardEntry{ConfigMap: m.shardName(zoneName), Serial: z.serial}
+	}
+	rendered, err := index.render()
+	if err != nil {
+		return nil, err
+	}
+	if rendered == "" {
+		delete(data, ShardsKey)
+	} else {
+		data[ShardsKey] = rendered
+	}
+	serveShards(interDomainServer, hostsPlugin, index, shards.zones)
+
+	// Every shard that held or will hold a zone file is written and pruned as needed.
+	names := make(map[string]bool)
+	for name := range shards.configMaps {
+		names[name] = true
+	}
+	for _, entry := range index {
+		names[entry.ConfigMap] = true
+	}
+	plan := &shardPlan{}
+	for _, name := range sortedKeys(names) {
+		cfg, err := m.getShard(ctx, shards, name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg == nil {
+			cfg = &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: m.namespace}}
+		}
+		shardData := maps.Clone(cfg.Data)
+		if shardData == nil {
+			shardData = map[string]string{}
+		}
+		for zoneName, entry := range index {
+			if entry.ConfigMap == name {
+				shardData[shardKey(zoneName)] = zones[zoneName].render()
+			}
+		}
+		if !maps.Equal(shardData, cfg.Data) {
+			write := cfg.DeepCopy()
+			write.Data = shardData
+			if err := checkConfigMapSize(write); err != nil {
+				return nil, err
+			}
+			plan.writes = append(plan.writes, write)
+			plan.previous = append(plan.previous, cfg.Data)
+		}
+		if len(unusedZoneFiles(name, shardData, index)) > 0 {
+			plan.prune = append(plan.prune, name)
+		}
+	}
+	return plan, nil
+}
+
+// serveShards makes the inter-domain server serve the zone file of every zone in index, and lets the
+// hosts plugin fall through to them. The zones of previous, the zones read from the shards, that are
+// no longer in index are removed from the fallthrough option.
+func serveShards(server *corefile.Server, hostsPlugin *corefile.Plugin, index shardIndex, previous map[string]*zone) {
+	plugins := server.Plugins[:0]
+	for _, p := range server.Plugins {
+		if !isShardFilePlugin(p) {
+			plugins = append(plugins, p)
+		}
+	}
+	server.Plugins = plugins
+
+	zoneNames := sortedKeys(index)
+	for _, zoneName := range zoneNames {
+		server.Plugins = append(server.Plugins, &corefile.Plugin{
+			Name: "file",
+			Args: []string{shardFilePath(zoneName), zoneName},
+		})
+	}
+
+	options := hostsPlugin.Options[:0]
+	found := false
+	for _, opt := range hostsPlugin.Options {
+		if opt.Name != "fallthrough" {
+			options = append(options, opt)
+			continue
+		}
+		found = true
+		// Without zones, the plugin already falls through for every zone.
+		if len(opt.Args) == 0 {
+			options = append(options, opt)
+			continue
+		}
+		args := opt.Args[:0]
+		for _, arg := range opt.Args {
+			if _, dropped := previous[arg]; !dropped || slices.Contains(zoneNames, arg) {
+				args = append(args, arg)
+			}
+		}
+		for _, zoneName := range zoneNames {
+			if !slices.Contains(args, zoneName) {
+				args = append(args, zoneName)
+			}
+		}
+		opt.Args = args
+		if len(args) > 0 {
+			options = append(options, opt)
+		}
+	}
+	if !found && len(zoneNames) > 0 {
+		options = append(options, &corefile.Option{Name: "fallthrough", Args: zoneNames})
+	}
+	hostsPlugin.Options = options
+}
+
+// unusedZoneFiles returns the zone file keys of data, the data of the shard ConfigMap name, that
+// index does not reference.
+func unusedZoneFiles(name string, data map[string]string, index shardIndex) []string {
+	var unused []string
+	for key := range data {
+		zoneName, ok := strings.CutPrefix(key, shardKey(""))
+		if !ok {
+			continue
+		}
+		if entry, found := index[zoneName]; !found || entry.ConfigMap != name {
+			unused = append(unused, key)
+		}
+	}
+	return unused
+}
+
+// write creates or updates the shard ConfigMaps of the plan.
+func (p *shardPlan) write(ctx context.Context, m *coreDNSManager) error {
+	for _, cfg := range p.writes {
+		client := m.shardClient(cfg.Name)
+		var err error
+		if cfg.ResourceVersion == "" {
+			err = client.Create(ctx, cfg)
+		} else {
+			err = client.Update(ctx, cfg)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write shard ConfigMap %q: %w", cfg.Name, err)
+		}
+		p.written++
+	}
+	return nil
+}
+
+// rollback restores the data of the shard ConfigMaps written by write, once the ConfigMap update
+// they went with has failed, so that the zone files of a failed attempt are neither left behind nor
+// read back by the next one. A shard modified since is left as is. Failures are only logged, as the
+// next update rewrites the shards and prunes the zone files that are not referenced.
+func (p *shardPlan) rollback(ctx context.Context, m *coreDNSManager) {
+	for i := p.written - 1; i >= 0; i-- {
+		restore := p.writes[i].DeepCopy()
+		restore.Data = p.previous[i]
+		if err := m.shardClient(restore.Name).Update(ctx, restore); err != nil {
+			log.Printf("could not restore DNS record shard %q: %v", restore.Name, err)
+		}
+	}
+	p.written = 0
+}
+
+// pruneShards removes the zone files that the ConfigMap no longer references from the given shards.
+// Failures are only logged, as unused zone files are harmless and are pruned on the next update.
+func (m *coreDNSManager) pruneShards(ctx context.Context, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	cfg, err := m.GetConfigMap(ctx)
+	if err != nil {
+		log.Printf("could not prune DNS record shards: %v", err)
+		return
+	}
+	index, err := parseShardIndex(cfg.Data[ShardsKey])
+	if err != nil {
+		log.Printf("could not prune DNS record shards: %v", err)
+		return
+	}
+	for _, name := range names {
+		client := m.shardClient(name)
+		err := retry.RetryOnConflict(conflictBackoff, func() error {
+			shard, err := client.Get(ctx)
+			if err != nil {
+				return err
+			}
+			unused := unusedZoneFiles(name, shard.Data, index)
+			if len(unused) == 0 {
+				return nil
+			}
+			for _, key := range unused {
+				delete(shard.Data, key)
+			}
+			return client.Update(ctx, shard)
+		})
+		if err != nil {
+			log.Printf("could not prune DNS record shard %q: %v", name, err)
+		}
+	}
+}
+
+// checkConfigMapSize returns an error if cfg is larger than the configured maximum, so that writes
+// fail with a clear error before the API server rejects them.
+func checkConfigMapSize(cfg *v1.ConfigMap) error {
+	size := 0
+	for key, value := range cfg.Data {
+		size += len(key) + len(value)
+	}
+	for key, value := range cfg.BinaryData {
+		size += len(key) + len(value)
+	}
+	if limit := env.GetConfigMapMaxSize(); size > limit {
+		return failedPrecondition("ConfigMap %q would be %d bytes, over the %d bytes limit; use DNS_RECORD_STORAGE=sharded or more DNS_RECORD_SHARDS", cfg.Name, size, limit)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
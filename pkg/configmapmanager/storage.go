@@ -0,0 +1,286 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	"k8s.io/client-go/util/retry"
+)
+
+// Storage modes of the A/AAAA records, selected with env.GetRecordStorage.
+const (
+	// StorageInline keeps the records as options of the hosts plugin, inside the Corefile.
+	StorageInline = "inline"
+	// StorageHostsFile keeps the records in a hosts-format file stored in the HostsKey data key,
+	// which the hosts plugin reads. Record changes then leave the Corefile untouched, and are
+	// written without parsing it.
+	StorageHostsFile = "hostsfile"
+	// StorageSharded spreads the records across several shard ConfigMaps, as zone files served by
+	// file plugins. See ShardsKey.
+	StorageSharded = "sharded"
+)
+
+// HostsKey is the ConfigMap data key holding the records in StorageHostsFile mode.
+const HostsKey = "l2sm.hosts"
+
+// defaultHostsFile is the file the hosts plugin reads when none is given.
+const defaultHostsFile = "/etc/hosts"
+
+// hostsFileHeader starts every hosts file written by the manager, so that the file exists even
+// when it holds no records.
+const hostsFileHeader = "# L2SM DNS entries, managed by l2sm-dns.\n"
+
+// hostsFilePath returns the path of the hosts file in the CoreDNS container.
+func hostsFilePath() string {
+	return path.Join(env.GetCoreDNSConfigDir(), HostsKey)
+}
+
+// interDomainHostsPlugin returns the hosts plugin of the inter-domain server block, if any.
+func interDomainHostsPlugin(cf *corefile.Corefile) (*corefile.Plugin, bool) {
+	interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+	if !ok {
+		return nil, false
+	}
+	return interDomainServer.GetPlugin("hosts")
+}
+
+// loadHostsFile merges the records of the HostsKey data key, if any, into the inter-domain hosts
+// plugin, so that they can be read and edited like inline records whatever the storage mode.
+// storeHostsFile moves them back according to the configured mode.
+func loadHostsFile(cf *corefile.Corefile, data map[string]string) error {
+	hostsPlugin, ok := interDomainHostsPlugin(cf)
+	if !ok {
+		return nil
+	}
+	content, ok := data[HostsKey]
+	if !ok {
+		return nil
+	}
+	records, err := hostsPlugin.ListHostsEntries()
+	if err != nil {
+		return err
+	}
+	for ip, names := range parseHostsFile(content) {
+		for _, name := range names {
+			if !slices.Contains(records[ip], name) {
+				records[ip] = append(records[ip], name)
+			}
+		}
+	}
+	delete(data, HostsKey)
+	return hostsPlugin.ReplaceHostsEntries(records)
+}
+
+// storeHostsFile lays the records of the inter-domain hosts plugin out according to the configured
+// storage mode: in StorageHostsFile mode they are moved to the HostsKey data key, which the plugin is
+// pointed at; otherwise they stay inline, and a plugin pointing at the HostsKey file is pointed back
+// at the default hosts file. Existing ConfigMaps are thus migrated on their next write.
+func storeHostsFile(cf *corefile.Corefile, data map[string]string) error {
+	hostsPlugin, ok := interDomainHostsPlugin(cf)
+	if !ok {
+		return nil
+	}
+
+	if env.GetRecordStorage() != StorageHostsFile {
+		if len(hostsPlugin.Args) > 0 && hostsPlugin.Args[0] == hostsFilePath() {
+			if len(hostsPlugin.Args) == 1 {
+				hostsPlugin.Args = nil
+			} else {
+				hostsPlugin.Args[0] = defaultHostsFile
+			}
+		}
+		return nil
+	}
+
+	records, err := hostsPlugin.ListHostsEntries()
+	if err != nil {
+		return err
+	}
+	// Render the records through a scratch plugin, to get the same ordering as inline records.
+	scratch := &corefile.Plugin{Name: "hosts"}
+	if err := scratch.ReplaceHostsEntries(records); err != nil {
+		return err
+	}
+	data[HostsKey] = renderHostsFile(scratch)
+
+	if err := hostsPlugin.ReplaceHostsEntries(map[string][]string{}); err != nil {
+		return err
+	}
+	if len(hostsPlugin.Args) == 0 {
+		hostsPlugin.Args = []string{hostsFilePath()}
+	} else {
+		hostsPlugin.Args[0] = hostsFilePath()
+	}
+	return nil
+}
+
+// renderHostsFile returns the hosts file of the entries of hostsPlugin.
+func renderHostsFile(hostsPlugin *corefile.Plugin) string {
+	var b strings.Builder
+	b.WriteString(hostsFileHeader)
+	for _, opt := range hostsPlugin.Options {
+		b.WriteString(opt.ToString() + "\n")
+	}
+	return b.String()
+}
+
+// parseHostsFile returns the ip -> []names records of a hosts-format file. Comments, and lines that
+// do not start with an IP address, are ignored.
+func parseHostsFile(content string) map[string][]string {
+	records := make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ip, err := NormalizeIP(fields[0])
+		if err != nil {
+			continue
+		}
+		records[ip] = append(records[ip], fields[1:]...)
+	}
+	return records
+}
+
+// updateHosts is updateCorefileAndLeases for mutations that only edit the A/AAAA records of the
+// inter-domain hosts plugin and the leases. In StorageHostsFile mode, they are applied to the HostsKey
+// data key alone, without parsing or rendering the Corefile, unless the Corefile has to change too.
+func (m *coreDNSManager) updateHosts(ctx context.Context, mutate func(hostsPlugin *corefile.Plugin, leases leaseTable) error) (map[string][]string, string, error) {
+	if env.GetRecordStorage() == StorageHostsFile {
+		records, resourceVersion, ok, err := m.updateHostsFile(ctx, mutate)
+		if ok || err != nil {
+			return records, resourceVersion, err
+		}
+	}
+	return m.updateCorefileAndLeases(ctx, func(cf *corefile.Corefile, leases leaseTable) error {
+		interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+		if !ok {
+			return failedPrecondition("could not find inter-domain port '%v' in Corefile, check corefile syntax", env.GetInterDomainDomPort())
+		}
+		hostsPlugin, ok := interDomainServer.GetPlugin("hosts")
+		if !ok {
+			return failedPrecondition("could not find 'hosts' plugin in the inter-domain server block")
+		}
+		return mutate(hostsPlugin, leases)
+	})
+}
+
+// updateHostsFile applies mutate to the records of the HostsKey data key, through a scratch hosts
+// plugin, and writes them back with the leases. It reports false, without writing, if the ConfigMap
+// is not laid out for StorageHostsFile yet, or if the reverse zones of the records change, as the
+// hosts plugin of the Corefile may then have to answer for other zones: the caller then goes through
+// the Corefile.
+func (m *coreDNSManager) updateHostsFile(ctx context.Context, mutate func(hostsPlugin *corefile.Plugin, leases leaseTable) error) (map[string][]string, string, bool, error) {
+	var records map[string][]string
+	var resourceVersion string
+	handled := false
+	err := retry.RetryOnConflict(conflictBackoff, func() error {
+		handled = false
+		cfg, err := m.GetConfigMap(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+		content, ok := cfg.Data[HostsKey]
+		if !ok || cfg.Data[ShardsKey] != "" {
+			return nil
+		}
+
+		current := parseHostsFile(content)
+		zones, err := recordZones(current)
+		if err != nil {
+			return err
+		}
+		hostsPlugin := &corefile.Plugin{Name: "hosts"}
+		if err := hostsPlugin.ReplaceHostsEntries(current); err != nil {
+			return err
+		}
+		leases, err := parseLeases(cfg.Data[LeasesKey])
+		if err != nil {
+			return err
+		}
+		if err := mutate(hostsPlugin, leases); err != nil {
+			return err
+		}
+		if records, err = hostsPlugin.ListHostsEntries(); err != nil {
+			return err
+		}
+		updatedZones, err := recordZones(records)
+		if err != nil {
+			return err
+		}
+		if !maps.Equal(zones, updatedZones) {
+			return nil
+		}
+		handled = true
+
+		pruneLeaseTable(leases, records)
+		data := maps.Clone(cfg.Data)
+		data[HostsKey] = renderHostsFile(hostsPlugin)
+		if err := storeLeases(data, leases); err != nil {
+			return err
+		}
+		if maps.Equal(data, cfg.Data) {
+			resourceVersion = cfg.ResourceVersion
+			return nil
+		}
+		cfg.Data = data
+		if err := checkConfigMapSize(cfg); err != nil {
+			return err
+		}
+		if err := m.cmClient.Update(ctx, cfg); err != nil {
+			return err
+		}
+		resourceVersion = cfg.ResourceVersion
+		return nil
+	})
+	return records, resourceVersion, handled, err
+}
+
+// recordZones returns the set of reverse zones of the addresses of the ip -> []names records.
+func recordZones(records map[string][]string) (map[string]bool, error) {
+	zones := make(map[string]bool)
+	for ip := range records {
+		zone, err := ReverseZone(ip)
+		if err != nil {
+			return nil, err
+		}
+		zones[zone] = true
+	}
+	return zones, nil
+}
+
+// MigrateRecordStorage rewrites the ConfigMap so that the records are laid out according to the
+// configured storage mode, e.g. moving inline records to the HostsKey data key. It is a no-op when
+// they already are.
+func (m *coreDNSManager) MigrateRecordStorage(ctx context.Context) error {
+	_, _, err := m.updateConfigMap(ctx, func(cf *corefile.Corefile, data map[string]string) error {
+		return nil
+	})
+	return err
+}
@@ -15,18 +15,97 @@
 package configmapmanager
 
 import (
-	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+)
+
+// DNSEntry holds the fields of an entry that its DNS name is made of.
+type DNSEntry = l2smzone.DNSEntry
+
+// NameTemplate turns DNSEntry fields into DNS names and back. See l2smzone.NameTemplate.
+type NameTemplate = l2smzone.NameTemplate
+
+// NewNameTemplate validates text and tld and compiles them into a NameTemplate.
+func NewNameTemplate(text, tld string) (*NameTemplate, error) {
+	return l2smzone.NewNameTemplate(text, tld)
+}
+
+var (
+	defaultNameTemplateOnce sync.Once
+	defaultNameTemplate     *NameTemplate
+	defaultNameTemplateErr  error
 )
 
-type DNSEntry struct {
-	PodName string
-	Network string
-	Scope   string
+// DefaultNameTemplate returns the NameTemplate configured through the environment, which
+// GenerateKey and ParseKey use.
+func DefaultNameTemplate() (*NameTemplate, error) {
+	defaultNameTemplateOnce.Do(func() {
+		defaultNameTemplate, defaultNameTemplateErr = NewNameTemplate(env.GetNameTemplate(), env.GetNameTLD())
+	})
+	return defaultNameTemplate, defaultNameTemplateErr
 }
 
+// GenerateKey returns the DNS name of dnsEntry using the configured naming template,
+// "<pod>.<network>.<scope>.l2sm" by default.
 func GenerateKey(dnsEntry DNSEntry) (string, error) {
-	if dnsEntry.PodName == "" || dnsEntry.Network == "" || dnsEntry.Scope == "" {
-		return "", fmt.Errorf("input entry has fields missing. All fields must be filled, received: %v", dnsEntry)
+	nt, err := DefaultNameTemplate()
+	if err != nil {
+		return "", err
+	}
+	return nt.GenerateKey(dnsEntry)
+}
+
+// ParseKey is the inverse of GenerateKey. It decodes a name back into the DNSEntry fields
+// referenced by the configured naming template, returning an error for names that were not
+// produced by GenerateKey.
+func ParseKey(key string) (DNSEntry, error) {
+	nt, err := DefaultNameTemplate()
+	if err != nil {
+		return DNSEntry{}, err
+	}
+	return nt.ParseKey(key)
+}
+
+// NormalizeIP validates ipAddress and returns its canonical text form, so that different notations
+// of the same address (e.g. "2001:db8:0:0::1" and "2001:db8::1") are stored only once.
+func NormalizeIP(ipAddress string) (string, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return "", invalidArgument("ip_address", "invalid IP address: %q", ipAddress)
+	}
+	return ip.String(), nil
+}
+
+// NormalizeIPs applies NormalizeIP to every address and drops duplicates, keeping the first occurrence.
+func NormalizeIPs(ipAddresses []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]struct{}, len(ipAddresses))
+	for _, ipAddress := range ipAddresses {
+		ip, err := NormalizeIP(ipAddress)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := seen[ip]; !found {
+			seen[ip] = struct{}{}
+			out = append(out, ip)
+		}
+	}
+	return out, nil
+}
+
+// addressesOf returns the IPs in the ip -> []domains records that dnsName is registered on.
+func addressesOf(records map[string][]string, dnsName string) []string {
+	var out []string
+	for ip, names := range records {
+		for _, name := range names {
+			if name == dnsName {
+				out = append(out, ip)
+				break
+			}
+		}
 	}
-	return fmt.Sprintf("%s.%s.%s.l2sm", dnsEntry.PodName, dnsEntry.Network, dnsEntry.Scope), nil
+	return out
 }
@@ -0,0 +1,312 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// RecordEventType is the kind of change reported by WatchDNSRecords.
+type RecordEventType string
+
+const (
+	RecordAdded   RecordEventType = "ADDED"
+	RecordDeleted RecordEventType = "DELETED"
+)
+
+// RecordEvent is a change to a single ip -> domain record. ResourceVersion is the version of
+// the ConfigMap in which the change was observed.
+type RecordEvent struct {
+	Type            RecordEventType
+	IPAddress       string
+	DNSName         string
+	ResourceVersion string
+}
+
+// watchRetryInterval is how long to wait before re-establishing a failed ConfigMap watch.
+const watchRetryInterval = 2 * time.Second
+
+// historySize is how many ConfigMap versions are remembered for ReplayDNSRecords.
+const historySize = 64
+
+// recordHistory remembers, for the latest ConfigMap versions seen by WatchDNSRecords, the bounds of
+// the records a client may hold after receiving part of the events of that version: every record
+// that was in the version or in one the events were diffed against (upper), and the records that
+// were in all of them (lower).
+type recordHistory struct {
+	mu       sync.Mutex
+	versions map[string]*recordBounds
+	order    []string
+}
+
+type recordBounds struct {
+	lower, upper map[string][]string
+}
+
+// add records that events turning previous into records were sent for resourceVersion. previous
+// is nil for the records a watch started at, for which no event was sent.
+func (h *recordHistory) add(resourceVersion string, previous, records map[string][]string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.versions == nil {
+		h.versions = make(map[string]*recordBounds)
+	}
+	bounds, found := h.versions[resourceVersion]
+	if !found {
+		bounds = &recordBounds{lower: copyRecords(records), upper: copyRecords(records)}
+		h.versions[resourceVersion] = bounds
+		h.order = append(h.order, resourceVersion)
+		if len(h.order) > historySize {
+			delete(h.versions, h.order[0])
+			h.order = h.order[1:]
+		}
+	}
+	if previous != nil {
+		bounds.lower = intersectRecords(bounds.lower, previous)
+		bounds.upper = mergeRecords(bounds.upper, previous)
+	}
+}
+
+// ReplayDNSRecords returns the events that bring a client that received events of resourceVersion,
+// possibly not all of them, to records. The events carry resourceVersion, so that a client that
+// receives only part of them can ask for them again. It returns false if resourceVersion is no
+// longer remembered, in which case the client needs a full snapshot.
+func (m *coreDNSManager) ReplayDNSRecords(resourceVersion string, records map[string][]string) ([]RecordEvent, bool) {
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	bounds, found := m.history.versions[resourceVersion]
+	if !found {
+		return nil, false
+	}
+	// Names only in upper may still be held and are deleted, names only in records may be missing
+	// and are added.
+	var events []RecordEvent
+	for _, event := range diffRecords(bounds.upper, records, resourceVersion) {
+		if event.Type == RecordDeleted {
+			events = append(events, event)
+		}
+	}
+	for _, event := range diffRecords(bounds.lower, records, resourceVersion) {
+		if event.Type == RecordAdded {
+			events = append(events, event)
+		}
+	}
+	return events, true
+}
+
+// WatchDNSRecords returns the current ip -> []domains records together with the ConfigMap
+// resourceVersion they were read at, and a channel that receives every later change to them.
+// The channel is closed once ctx is done. Dropped watches are re-established transparently.
+func (m *coreDNSManager) WatchDNSRecords(ctx context.Context) (map[string][]string, string, <-chan RecordEvent, error) {
+	cfg, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+	records, err := m.listHostsRecords(ctx, cfg)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// Open the first watch before returning, so no change made after this call can be missed.
+	w, err := m.cmClient.Watch(ctx, cfg.ResourceVersion)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to watch ConfigMap: %w", err)
+	}
+
+	m.history.add(cfg.ResourceVersion, nil, records)
+	events := make(chan RecordEvent)
+	go m.watchRecords(ctx, w, copyRecords(records), cfg.ResourceVersion, events)
+	return records, cfg.ResourceVersion, events, nil
+}
+
+func (m *coreDNSManager) watchRecords(ctx context.Context, w watch.Interface, current map[string][]string, resourceVersion string, events chan<- RecordEvent) {
+	defer close(events)
+
+	// publish diffs the records in cfg against the last known ones and sends the changes.
+	publish := func(cfg *v1.ConfigMap, records map[string][]string) bool {
+		m.history.add(cfg.ResourceVersion, current, records)
+		for _, event := range diffRecords(current, records, cfg.ResourceVersion) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		current, resourceVersion = records, cfg.ResourceVersion
+		return true
+	}
+
+	// resync re-reads the ConfigMap when the watch can no longer be resumed from resourceVersion.
+	resync := func() bool {
+		cfg, err := m.GetConfigMap(ctx)
+		if err != nil {
+			log.Printf("could not resync DNS records: %v", err)
+			return true
+		}
+		records, err := m.listHostsRecords(ctx, cfg)
+		if err != nil {
+			log.Printf("could not resync DNS records: %v", err)
+			return true
+		}
+		return publish(cfg, records)
+	}
+
+	for ctx.Err() == nil {
+		if w == nil {
+			var err error
+			w, err = m.cmClient.Watch(ctx, resourceVersion)
+			if err != nil {
+				log.Printf("could not watch ConfigMap, retrying in %v: %v", watchRetryInterval, err)
+				select {
+				case <-time.After(watchRetryInterval):
+				case <-ctx.Done():
+					return
+				}
+				if !resync() {
+					return
+				}
+				continue
+			}
+		}
+
+		ok := m.consumeWatch(ctx, w, publish, resync)
+		w.Stop()
+		w = nil
+		if !ok {
+			return
+		}
+	}
+}
+
+// consumeWatch forwards the events of w until it is closed. It returns false once ctx is done.
+func (m *coreDNSManager) consumeWatch(ctx context.Context, w watch.Interface, publish func(*v1.ConfigMap, map[string][]string) bool, resync func() bool) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case ev, open := <-w.ResultChan():
+			if !open {
+				return true
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				cfg, isConfigMap := ev.Object.(*v1.ConfigMap)
+				if !isConfigMap || cfg.Name != m.configMap {
+					continue
+				}
+				records, err := m.listHostsRecords(ctx, cfg)
+				if err != nil {
+					log.Printf("ignoring unreadable ConfigMap version %s: %v", cfg.ResourceVersion, err)
+					continue
+				}
+				if !publish(cfg, records) {
+					return false
+				}
+			case watch.Deleted:
+				cfg, isConfigMap := ev.Object.(*v1.ConfigMap)
+				if !isConfigMap || cfg.Name != m.configMap {
+					continue
+				}
+				if !publish(cfg, map[string][]string{}) {
+					return false
+				}
+			case watch.Error:
+				// Typically "resource version too old": start over from a fresh read.
+				if !resync() {
+					return false
+				}
+				return true
+			}
+		}
+	}
+}
+
+// diffRecords returns the events that turn old into updated, deletions first, in a stable order.
+func diffRecords(old, updated map[string][]string, resourceVersion string) []RecordEvent {
+	var deleted, added []RecordEvent
+	for ip, names := range old {
+		for _, name := range removeNames(names, updated[ip]) {
+			deleted = append(deleted, RecordEvent{Type: RecordDeleted, IPAddress: ip, DNSName: name, ResourceVersion: resourceVersion})
+		}
+	}
+	for ip, names := range updated {
+		for _, name := range removeNames(names, old[ip]) {
+			added = append(added, RecordEvent{Type: RecordAdded, IPAddress: ip, DNSName: name, ResourceVersion: resourceVersion})
+		}
+	}
+	sortEvents(deleted)
+	sortEvents(added)
+	return append(deleted, added...)
+}
+
+// removeNames returns the names in base that are not in other.
+func removeNames(base, other []string) []string {
+	set := make(map[string]struct{}, len(other))
+	for _, name := range other {
+		set[name] = struct{}{}
+	}
+	var out []string
+	for _, name := range base {
+		if _, found := set[name]; !found {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func sortEvents(events []RecordEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].DNSName != events[j].DNSName {
+			return events[i].DNSName < events[j].DNSName
+		}
+		return events[i].IPAddress < events[j].IPAddress
+	})
+}
+
+// intersectRecords returns the records that are both in a and in b.
+func intersectRecords(a, b map[string][]string) map[string][]string {
+	out := make(map[string][]string)
+	for ip, names := range a {
+		if kept := removeNames(names, removeNames(names, b[ip])); len(kept) > 0 {
+			out[ip] = kept
+		}
+	}
+	return out
+}
+
+// mergeRecords returns the records that are in a or in b.
+func mergeRecords(a, b map[string][]string) map[string][]string {
+	out := copyRecords(a)
+	for ip, names := range b {
+		out[ip] = append(out[ip], removeNames(names, out[ip])...)
+	}
+	return out
+}
+
+func copyRecords(records map[string][]string) map[string][]string {
+	out := make(map[string][]string, len(records))
+	for ip, names := range records {
+		out[ip] = append([]string(nil), names...)
+	}
+	return out
+}
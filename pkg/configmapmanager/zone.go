@@ -0,0 +1,312 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	"github.com/miekg/dns"
+)
+
+// Record types that can be registered besides the A/AAAA records of the hosts plugin.
+const (
+	RecordTypeCNAME = l2smzone.RecordTypeCNAME
+	RecordTypeSRV   = l2smzone.RecordTypeSRV
+	RecordTypeTXT   = l2smzone.RecordTypeTXT
+)
+
+// ZoneKey is the ConfigMap data key holding the RFC 1035 zone file of the CNAME, SRV and TXT records
+// under the L2SM TLD. It is served by a file plugin in the inter-domain server block, which is only
+// present while the zone holds records.
+const ZoneKey = "l2sm.db"
+
+// defaultRecordTTL is the TTL of records registered without one, the same as the hosts plugin's.
+const defaultRecordTTL = l2smzone.DefaultRecordTTL
+
+// Record is a CNAME, SRV or TXT record. Name is the owner name, which must be under the L2SM TLD.
+type Record = l2smzone.Record
+
+// zoneOrigin returns the fully qualified name of the L2SM zone.
+func zoneOrigin() string {
+	return dns.Fqdn(env.GetNameTLD())
+}
+
+// recordFromRR is the inverse of Record.RR. It returns false for record types that are not modeled.
+func recordFromRR(rr dns.RR) (Record, bool) {
+	hdr := rr.Header()
+	r := Record{Name: strings.TrimSuffix(hdr.Name, "."), TTL: hdr.Ttl}
+	switch rr := rr.(type) {
+	case *dns.CNAME:
+		r.Type, r.Target = RecordTypeCNAME, strings.TrimSuffix(rr.Target, ".")
+	case *dns.SRV:
+		r.Type, r.Target = RecordTypeSRV, strings.TrimSuffix(rr.Target, ".")
+		r.Priority, r.Weight, r.Port = rr.Priority, rr.Weight, rr.Port
+	case *dns.TXT:
+		r.Type, r.Text = RecordTypeTXT, rr.Txt
+	default:
+		return Record{}, false
+	}
+	return r, true
+}
+
+// zone is the parsed content of a zone file, such as the one of the ZoneKey data key.
+type zone struct {
+	origin  string
+	serial  uint32
+	records []dns.RR
+}
+
+func parseZone(data string) (*zone, error) {
+	return parseZoneFile(data, zoneOrigin(), ZoneKey)
+}
+
+// parseZoneFile parses the zone file of the given origin stored in the key data key.
+func parseZoneFile(data, origin, key string) (*zone, error) {
+	z := &zone{origin: origin}
+	if data == "" {
+		return z, nil
+	}
+	parser := dns.NewZoneParser(strings.NewReader(data), origin, key)
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		if soa, isSOA := rr.(*dns.SOA); isSOA {
+			z.serial = soa.Serial
+			continue
+		}
+		z.records = append(z.records, rr)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, failedPrecondition("could not parse %s in ConfigMap data: %v", key, err)
+	}
+	return z, nil
+}
+
+// render returns the zone file, or "" if the zone holds no records. Records are sorted, so an
+// unchanged zone always renders the same.
+func (z *zone) render() string {
+	if len(z.records) == 0 {
+		return ""
+	}
+	z.sort()
+
+	origin := z.origin
+	soa := &dns.SOA{
+		Hdr:     dns.RR_Header{Name: origin, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: defaultRecordTTL},
+		Ns:      "ns.dns." + origin,
+		Mbox:    "hostmaster." + origin,
+		Serial:  z.serial,
+		Refresh: 7200,
+		Retry:   1800,
+		Expire:  86400,
+		Minttl:  30,
+	}
+	lines := []string{soa.String()}
+	for _, rr := range z.records {
+		lines = append(lines, rr.String())
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// sort orders the records by name, type and content.
+func (z *zone) sort() {
+	sort.SliceStable(z.records, func(i, j int) bool {
+		a, b := z.records[i].Header(), z.records[j].Header()
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Rrtype != b.Rrtype {
+			return a.Rrtype < b.Rrtype
+		}
+		return z.records[i].String() < z.records[j].String()
+	})
+}
+
+// add adds rr, or updates the TTL of an identical record. A CNAME cannot share its name with other
+// records.
+func (z *zone) add(rr dns.RR) error {
+	for _, existing := range z.records {
+		if dns.IsDuplicate(existing, rr) {
+			existing.Header().Ttl = rr.Header().Ttl
+			return nil
+		}
+		if existing.Header().Name != rr.Header().Name {
+			continue
+		}
+		if existing.Header().Rrtype == dns.TypeCNAME || rr.Header().Rrtype == dns.TypeCNAME {
+			return newError(ErrAlreadyExists, "name", "a CNAME record cannot share the name %q with other records", strings.TrimSuffix(rr.Header().Name, "."))
+		}
+	}
+	z.records = append(z.records, rr)
+	return nil
+}
+
+// remove deletes the records that are duplicates of rr and returns how many it removed.
+func (z *zone) remove(rr dns.RR) int {
+	kept := z.records[:0]
+	removed := 0
+	for _, existing := range z.records {
+		if dns.IsDuplicate(existing, rr) {
+			removed++
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	z.records = kept
+	return removed
+}
+
+// zoneFilePath returns the path of the zone file in the CoreDNS container.
+func zoneFilePath() string {
+	return path.Join(env.GetCoreDNSConfigDir(), ZoneKey)
+}
+
+// updateZone applies mutate to the zone stored in the ConfigMap. When the zone changes, its serial is
+// increased so the file plugin reloads it, and the file plugin is added to or removed from the
+// inter-domain server block depending on whether the zone holds any record.
+func (m *coreDNSManager) updateZone(ctx context.Context, mutate func(cf *corefile.Corefile, z *zone) error) error {
+	_, _, err := m.updateConfigMap(ctx, func(cf *corefile.Corefile, data map[string]string) error {
+		z, err := parseZone(data[ZoneKey])
+		if err != nil {
+			return err
+		}
+
+		interDomainServer, ok := cf.GetServer(env.GetInterDomainDomPort())
+		if !ok {
+			return failedPrecondition("could not find inter-domain port '%v' in Corefile, check corefile syntax", env.GetInterDomainDomPort())
+		}
+
+		before := z.render()
+		if err := mutate(cf, z); err != nil {
+			return err
+		}
+		after := z.render()
+		if after == before {
+			return nil
+		}
+
+		if after == "" {
+			delete(data, ZoneKey)
+			removeFilePlugin(interDomainServer)
+			return nil
+		}
+		z.serial++
+		data[ZoneKey] = z.render()
+		ensureFilePlugin(interDomainServer)
+		return nil
+	})
+	return err
+}
+
+// ensureFilePlugin makes the inter-domain server serve the zone file, and lets the hosts plugin fall
+// through to it for the names of the zone it does not know.
+func ensureFilePlugin(server *corefile.Server) {
+	if _, ok := server.GetPlugin("file"); !ok {
+		server.Plugins = append(server.Plugins, &corefile.Plugin{
+			Name: "file",
+			Args: []string{zoneFilePath(), env.GetNameTLD()},
+		})
+	}
+	hostsPlugin, ok := server.GetPlugin("hosts")
+	if !ok {
+		return
+	}
+	for _, opt := range hostsPlugin.Options {
+		if opt.Name == "fallthrough" {
+			return
+		}
+	}
+	hostsPlugin.Options = append(hostsPlugin.Options, &corefile.Option{Name: "fallthrough", Args: []string{env.GetNameTLD()}})
+}
+
+// removeFilePlugin removes the file plugin serving the zone file. The fallthrough option of the hosts
+// plugin is kept, as it may have been set by hand.
+func removeFilePlugin(server *corefile.Server) {
+	plugins := server.Plugins[:0]
+	for _, p := range server.Plugins {
+		if p.Name == "file" && len(p.Args) > 0 && p.Args[0] == zoneFilePath() {
+			continue
+		}
+		plugins = append(plugins, p)
+	}
+	server.Plugins = plugins
+}
+
+// AddRecord registers a CNAME, SRV or TXT record. Adding a record that already exists only updates
+// its TTL.
+func (m *coreDNSManager) AddRecord(ctx context.Context, record Record) error {
+	rr, err := record.RR(env.GetNameTLD())
+	if err != nil {
+		return err
+	}
+
+	return m.updateZone(ctx, func(cf *corefile.Corefile, z *zone) error {
+		if record.Type == RecordTypeCNAME {
+			// The name must not have A/AAAA records in the hosts plugin either.
+			interDomainServer, _ := cf.GetServer(env.GetInterDomainDomPort())
+			if hostsPlugin, ok := interDomainServer.GetPlugin("hosts"); ok {
+				records, err := hostsPlugin.ListHostsEntries()
+				if err != nil {
+					return err
+				}
+				if len(addressesOf(records, strings.TrimSuffix(rr.Header().Name, "."))) > 0 {
+					return newError(ErrAlreadyExists, "name", "a CNAME record cannot share the name %q with other records", record.Name)
+				}
+			}
+		}
+		return z.add(rr)
+	})
+}
+
+// RemoveRecord unregisters a CNAME, SRV or TXT record. The TTL of record is ignored.
+func (m *coreDNSManager) RemoveRecord(ctx context.Context, record Record) error {
+	rr, err := record.RR(env.GetNameTLD())
+	if err != nil {
+		return err
+	}
+
+	return m.updateZone(ctx, func(cf *corefile.Corefile, z *zone) error {
+		if z.remove(rr) == 0 {
+			return notFound("%s record %q not found", record.Type, record.Name)
+		}
+		return nil
+	})
+}
+
+// ListRecords returns the registered CNAME, SRV and TXT records, sorted by name and type.
+func (m *coreDNSManager) ListRecords(ctx context.Context) ([]Record, error) {
+	cfg, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+	z, err := parseZone(cfg.Data[ZoneKey])
+	if err != nil {
+		return nil, err
+	}
+	z.sort()
+
+	records := []Record{}
+	for _, rr := range z.records {
+		if record, ok := recordFromRR(rr); ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
@@ -166,3 +166,25 @@ func (c *Corefile) AddServer(server Server) error {
 	c.Servers = append(c.Servers, &server)
 	return nil
 }
+
+// RemoveServer deletes the server block that exactly matches the given domPorts.
+func (c *Corefile) RemoveServer(domPorts ...string) error {
+	existing, ok := c.GetServer(domPorts...)
+	if !ok {
+		return fmt.Errorf("server %v not found in corefile", strings.Join(domPorts, " "))
+	}
+	for i, s := range c.Servers {
+		if s == existing {
+			c.Servers = append(c.Servers[:i], c.Servers[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ListServers returns the server blocks of the Corefile, in the order they are declared.
+func (c *Corefile) ListServers() []*Server {
+	servers := make([]*Server, len(c.Servers))
+	copy(servers, c.Servers)
+	return servers
+}
@@ -15,7 +15,10 @@
 package corefile
 
 import (
+	"bytes"
 	"fmt"
+	"net"
+	"sort"
 	"strings"
 )
 
@@ -26,6 +29,9 @@ type Plugin struct {
 }
 
 // ListHostsEntries collects and returns a map of IP -> []domains from the hosts plugin options.
+// IPs are returned in their canonical form (e.g. compressed IPv6), so entries written with different
+// notations of the same address are merged. Options that are not host entries (e.g. fallthrough, ttl
+// or reload) are ignored.
 func (p *Plugin) ListHostsEntries() (map[string][]string, error) {
 	if p.Name != "hosts" {
 		return nil, fmt.Errorf("plugin %s is not 'hosts'", p.Name)
@@ -34,30 +40,52 @@ func (p *Plugin) ListHostsEntries() (map[string][]string, error) {
 	result := make(map[string][]string)
 	for _, opt := range p.Options {
 		// Each Option typically is:  <ip> <domain1> <domain2> ...
-		ip := opt.Name
-		if ip == "" {
+		if !isHostsEntry(opt) {
 			continue
 		}
+		ip := normalizeIP(opt.Name)
 		// The rest of the Args are domain names
-		result[ip] = append(result[ip], opt.Args...)
+		result[ip] = uniqueStrings(append(result[ip], opt.Args...))
 	}
 
 	return result, nil
 }
 
 // ReplaceHostsEntries takes a map of ip -> []domains and replaces the plugin’s entire set of host entries.
+// IPs are written in canonical form and sorted, with the domains of each IP kept in the given order, so that
+// rewriting the same set of entries always renders the same hosts block. Options that are not host
+// entries are kept after the entries, in their original order.
 func (p *Plugin) ReplaceHostsEntries(entries map[string][]string) error {
 	if p.Name != "hosts" {
 		return fmt.Errorf("plugin %s is not 'hosts'", p.Name)
 	}
 
-	var newOptions []*Option
+	merged := make(map[string][]string, len(entries))
 	for ip, domains := range entries {
+		ip = normalizeIP(ip)
+		merged[ip] = uniqueStrings(append(merged[ip], domains...))
+	}
+
+	ips := make([]string, 0, len(merged))
+	for ip := range merged {
+		ips = append(ips, ip)
+	}
+	sort.Slice(ips, func(i, j int) bool {
+		return CompareIPs(ips[i], ips[j]) < 0
+	})
+
+	var newOptions []*Option
+	for _, ip := range ips {
 		newOptions = append(newOptions, &Option{
 			Name: ip,
-			Args: domains,
+			Args: merged[ip],
 		})
 	}
+	for _, opt := range p.Options {
+		if !isHostsEntry(opt) {
+			newOptions = append(newOptions, opt)
+		}
+	}
 	p.Options = newOptions
 	return nil
 }
@@ -70,6 +98,7 @@ func (p *Plugin) AddHostsEntries(entries map[string][]string) error {
 	}
 
 	for ip, newDomains := range entries {
+		ip = normalizeIP(ip)
 		existing[ip] = append(existing[ip], newDomains...)
 		// optionally deduplicate
 		existing[ip] = uniqueStrings(existing[ip])
@@ -85,6 +114,7 @@ func (p *Plugin) RemoveHostsEntries(entries map[string][]string) error {
 	}
 
 	for ip, rmDomains := range entries {
+		ip = normalizeIP(ip)
 		if _, found := existing[ip]; !found {
 			continue
 		}
@@ -133,3 +163,42 @@ NextPlugin:
 	}
 	return nil, false
 }
+
+// isHostsEntry reports whether a hosts plugin option is an "<ip> <domains...>" entry.
+func isHostsEntry(opt *Option) bool {
+	return net.ParseIP(opt.Name) != nil
+}
+
+// normalizeIP returns the canonical text form of ip, or ip unchanged if it is not an address.
+func normalizeIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
+// CompareIPs orders IPv4 addresses before IPv6 ones, and addresses of the same family by value, e.g.
+// 10.0.0.9 before 10.0.0.10. Strings that are not IP addresses are ordered lexicographically after
+// every address.
+func CompareIPs(a, b string) int {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	switch {
+	case ipA == nil && ipB == nil:
+		return strings.Compare(a, b)
+	case ipA == nil:
+		return 1
+	case ipB == nil:
+		return -1
+	}
+	v4A, v4B := ipA.To4() != nil, ipB.To4() != nil
+	if v4A != v4B {
+		if v4A {
+			return -1
+		}
+		return 1
+	}
+	if c := bytes.Compare(ipA.To16(), ipB.To16()); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package l2smdns is a CoreDNS plugin that answers the queries for the L2SM zone from the DnsService
+// of an l2sm-dns server, instead of from a Corefile rewritten by it. The entries are kept in a local
+// cache, which a WatchEntries stream updates as soon as they change.
+//
+// To build it into CoreDNS, add it to the plugin.cfg of CoreDNS, e.g. before the hosts plugin:
+//
+//	l2smdns:github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smdns
+//
+// Syntax:
+//
+//	l2smdns [ADDRESS] {
+//	    tld TLD
+//	    template TEMPLATE
+//	    refresh DURATION
+//	    fallthrough [ZONES...]
+//	}
+//
+// ADDRESS is the gRPC address of the l2sm-dns server, localhost:8081 by default. TLD and TEMPLATE
+// must match the DNS_TLD and DNS_NAME_TEMPLATE of the server. The CNAME, SRV and TXT records are
+// re-read every DURATION, 30s by default. With fallthrough, names of the zone that do not exist are
+// passed on to the next plugin.
+package l2smdns
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/pkg/fall"
+	"github.com/coredns/coredns/request"
+	"github.com/miekg/dns"
+)
+
+// L2SMDNS is the l2smdns plugin.
+type L2SMDNS struct {
+	Next plugin.Handler
+	Fall fall.F
+
+	// Addr is the gRPC address of the l2sm-dns server.
+	Addr string
+	// Names turns the entries into DNS names, as the server does.
+	Names           *l2smzone.NameTemplate
+	RefreshInterval time.Duration
+
+	zone   *l2smzone.Zone
+	synced atomic.Bool
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New returns the plugin for the server at addr, which serves the names of names.
+func New(addr string, names *l2smzone.NameTemplate) *L2SMDNS {
+	return &L2SMDNS{
+		Addr:            addr,
+		Names:           names,
+		RefreshInterval: defaultRefreshInterval,
+		zone:            l2smzone.NewZone(names.TLD()),
+	}
+}
+
+// ServeDNS implements plugin.Handler.
+func (l *L2SMDNS) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) (int, error) {
+	state := request.Request{W: w, Req: r}
+	if !l.zone.Matches(state.Name()) {
+		return plugin.NextOrFailure(l.Name(), l.Next, ctx, w, r)
+	}
+
+	reply := l.zone.Answer(r)
+	if reply.Rcode == dns.RcodeNameError && l.Fall.Through(state.Name()) {
+		return plugin.NextOrFailure(l.Name(), l.Next, ctx, w, r)
+	}
+	if err := w.WriteMsg(reply); err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	return dns.RcodeSuccess, nil
+}
+
+// Name implements plugin.Handler.
+func (l *L2SMDNS) Name() string { return "l2smdns" }
+
+// Ready implements ready.Readiness: the plugin is ready once it received the entries of the server.
+func (l *L2SMDNS) Ready() bool { return l.synced.Load() }
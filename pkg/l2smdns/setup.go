@@ -0,0 +1,119 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2smdns
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	"github.com/coredns/caddy"
+	"github.com/coredns/coredns/core/dnsserver"
+	"github.com/coredns/coredns/plugin"
+	clog "github.com/coredns/coredns/plugin/pkg/log"
+)
+
+var log = clog.NewWithPlugin("l2smdns")
+
+// defaultAddr is the gRPC address of the l2sm-dns server when none is given.
+const defaultAddr = "localhost:8081"
+
+func init() { plugin.Register("l2smdns", setup) }
+
+func setup(c *caddy.Controller) error {
+	l, err := Parse(c)
+	if err != nil {
+		return plugin.Error("l2smdns", err)
+	}
+
+	c.OnStartup(l.Start)
+	c.OnShutdown(l.Stop)
+
+	dnsserver.GetConfig(c).AddPlugin(func(next plugin.Handler) plugin.Handler {
+		l.Next = next
+		return l
+	})
+	return nil
+}
+
+// Parse parses the l2smdns directive. The TLD and the naming template default to those of the
+// DNS_TLD and DNS_NAME_TEMPLATE environment variables, like the server's.
+func Parse(c *caddy.Controller) (*L2SMDNS, error) {
+	addr := defaultAddr
+	tld := env.GetNameTLD()
+	template := env.GetNameTemplate()
+	refresh := defaultRefreshInterval
+	var fallthroughZones []string
+	fall := false
+
+	i := 0
+	for c.Next() {
+		if i > 0 {
+			return nil, plugin.ErrOnce
+		}
+		i++
+
+		args := c.RemainingArgs()
+		switch len(args) {
+		case 0:
+		case 1:
+			addr = args[0]
+		default:
+			return nil, c.ArgErr()
+		}
+
+		for c.NextBlock() {
+			switch c.Val() {
+			case "tld":
+				if !c.NextArg() {
+					return nil, c.ArgErr()
+				}
+				tld = c.Val()
+			case "template":
+				args := c.RemainingArgs()
+				if len(args) == 0 {
+					return nil, c.ArgErr()
+				}
+				template = strings.Join(args, " ")
+			case "refresh":
+				if !c.NextArg() {
+					return nil, c.ArgErr()
+				}
+				d, err := time.ParseDuration(c.Val())
+				if err != nil || d <= 0 {
+					return nil, c.Errf("invalid refresh interval %q", c.Val())
+				}
+				refresh = d
+			case "fallthrough":
+				fall = true
+				fallthroughZones = c.RemainingArgs()
+			default:
+				return nil, c.Errf("unknown property %q", c.Val())
+			}
+		}
+	}
+
+	names, err := l2smzone.NewNameTemplate(template, tld)
+	if err != nil {
+		return nil, c.Errf("invalid naming configuration: %v", err)
+	}
+	l := New(addr, names)
+	l.RefreshInterval = refresh
+	if fall {
+		l.Fall.SetZonesFromArgs(fallthroughZones)
+	}
+	return l, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2smdns
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// defaultRefreshInterval is how often the CNAME, SRV and TXT records are re-read by default.
+const defaultRefreshInterval = 30 * time.Second
+
+// retryInterval is how long to wait before re-establishing a failed watch.
+const retryInterval = 2 * time.Second
+
+// recordTypes maps the API record types to the manager ones.
+var recordTypes = map[dns.RecordType]string{
+	dns.RecordType_RECORD_TYPE_CNAME: l2smzone.RecordTypeCNAME,
+	dns.RecordType_RECORD_TYPE_SRV:   l2smzone.RecordTypeSRV,
+	dns.RecordType_RECORD_TYPE_TXT:   l2smzone.RecordTypeTXT,
+}
+
+// Start connects to the server, and keeps the cache in sync with it until Stop is called.
+func (l *L2SMDNS) Start() error {
+	conn, err := grpc.NewClient(l.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %w", l.Addr, err)
+	}
+	client := dns.NewDnsServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	l.mu.Lock()
+	l.cancel, l.done = cancel, done
+	l.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		defer conn.Close()
+		go l.refreshRecords(ctx, client)
+		l.watchEntries(ctx, client)
+	}()
+	return nil
+}
+
+// Stop closes the connection to the server.
+func (l *L2SMDNS) Stop() error {
+	l.mu.Lock()
+	cancel, done := l.cancel, l.done
+	l.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	return nil
+}
+
+// watchEntries applies the entries streamed by WatchEntries to the cache, and re-establishes the
+// stream when it fails, resuming from the last resource version seen.
+func (l *L2SMDNS) watchEntries(ctx context.Context, client dns.DnsServiceClient) {
+	resourceVersion := ""
+	for ctx.Err() == nil {
+		stream, err := client.WatchEntries(ctx, &dns.WatchEntriesRequest{ResourceVersion: resourceVersion})
+		if err == nil {
+			resourceVersion, err = l.consume(stream, resourceVersion)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Watch of %s failed, retrying in %v: %v", l.Addr, retryInterval, err)
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// consume applies the events of stream until it fails, and returns the last resource version seen.
+func (l *L2SMDNS) consume(stream dns.DnsService_WatchEntriesClient, resourceVersion string) (string, error) {
+	// The snapshot replaces the cache once complete. The server replays the changes instead if it
+	// still remembers the version the cache was built from.
+	snapshot := map[string][]string{}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return resourceVersion, err
+		}
+		switch resp.GetType() {
+		case dns.EventType_EVENT_TYPE_ADDED:
+			name, err := l.entryName(resp.GetEntry())
+			if err != nil {
+				log.Warningf("Skipping entry: %v", err)
+				break
+			}
+			for _, ip := range resp.GetEntry().GetIpAddresses() {
+				if resp.GetSnapshot() {
+					snapshot[ip] = append(snapshot[ip], name)
+				} else {
+					l.zone.AddHost(ip, name)
+				}
+			}
+		case dns.EventType_EVENT_TYPE_DELETED:
+			name, err := l.entryName(resp.GetEntry())
+			if err != nil {
+				break
+			}
+			for _, ip := range resp.GetEntry().GetIpAddresses() {
+				l.zone.RemoveHost(ip, name)
+			}
+		case dns.EventType_EVENT_TYPE_SYNCED:
+			if resp.GetSnapshot() {
+				l.zone.SetHosts(snapshot)
+			}
+			l.synced.Store(true)
+		}
+		resourceVersion = resp.GetResourceVersion()
+	}
+}
+
+// entryName returns the DNS name of entry.
+func (l *L2SMDNS) entryName(entry *dns.DNSEntry) (string, error) {
+	return l.Names.GenerateKey(l2smzone.DNSEntry{
+		PodName:   entry.GetPodName(),
+		Network:   entry.GetNetwork(),
+		Scope:     entry.GetScope(),
+		Namespace: entry.GetNamespace(),
+		Cluster:   entry.GetCluster(),
+	})
+}
+
+// refreshRecords re-reads the CNAME, SRV and TXT records every RefreshInterval.
+func (l *L2SMDNS) refreshRecords(ctx context.Context, client dns.DnsServiceClient) {
+	ticker := time.NewTicker(l.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := l.loadRecords(ctx, client); err != nil && ctx.Err() == nil {
+			log.Warningf("Could not list the records of %s: %v", l.Addr, err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *L2SMDNS) loadRecords(ctx context.Context, client dns.DnsServiceClient) error {
+	resp, err := client.ListRecords(ctx, &dns.ListRecordsRequest{})
+	if err != nil {
+		return err
+	}
+	records := make([]l2smzone.Record, 0, len(resp.GetRecords()))
+	for _, record := range resp.GetRecords() {
+		recordType, ok := recordTypes[record.GetType()]
+		if !ok || record.GetPriority() > math.MaxUint16 || record.GetWeight() > math.MaxUint16 || record.GetPort() > math.MaxUint16 {
+			continue
+		}
+		records = append(records, l2smzone.Record{
+			Name:     record.GetName(),
+			Type:     recordType,
+			TTL:      record.GetTtl(),
+			Target:   record.GetTarget(),
+			Priority: uint16(record.GetPriority()),
+			Weight:   uint16(record.GetWeight()),
+			Port:     uint16(record.GetPort()),
+			Text:     record.GetText(),
+		})
+	}
+	l.zone.SetRecords(records)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package l2smzone holds what the server and the CoreDNS plugin share about the L2SM zone: the
+// naming template of the entries, the CNAME, SRV and TXT records, the kinds of their errors, and an
+// in-memory copy of the zone to answer queries from. It has no Kubernetes dependencies, so that the
+// plugin does not pull them into CoreDNS.
+package l2smzone
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of the errors returned for the entries and records, which they match with errors.Is.
+// configmapmanager uses the same kinds for the errors of a DNSManager.
+var (
+	// ErrInvalidArgument is returned for invalid entries, addresses, records or servers.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrNotFound is returned for entries, records, servers or ConfigMaps that do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when a record clashes with the ones that exist.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrFailedPrecondition is returned when the CoreDNS configuration cannot take the change, e.g.
+	// it has no hosts plugin, until it is fixed.
+	ErrFailedPrecondition = errors.New("failed precondition")
+	// ErrConflict is returned when the ConfigMap was modified concurrently; the call can be retried.
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable is returned when the storage cannot be reached; the call can be retried.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error is an error of a given kind. For ErrInvalidArgument, Field names the offending field.
+type Error struct {
+	Kind error
+	// Field is the snake_case name of the field of the DNSService API at fault, relative to the
+	// entry, record or server it belongs to, e.g. "ip_address".
+	Field string
+	Err   error
+}
+
+// Error returns the message of the underlying error, without the kind.
+func (e *Error) Error() string { return e.Err.Error() }
+
+// Unwrap returns both the kind and the underlying error, for errors.Is and errors.As.
+func (e *Error) Unwrap() []error { return []error{e.Kind, e.Err} }
+
+// invalidArgument returns an ErrInvalidArgument error about field.
+func invalidArgument(field string, format string, args ...any) error {
+	return &Error{Kind: ErrInvalidArgument, Field: field, Err: fmt.Errorf(format, args...)}
+}
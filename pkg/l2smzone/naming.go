@@ -0,0 +1,239 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2smzone
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// DNSEntry holds the fields of an entry that its DNS name is made of.
+type DNSEntry struct {
+	PodName   string
+	Network   string
+	Scope     string
+	Namespace string
+	Cluster   string
+}
+
+// templateFields are the DNSEntry fields a naming template may reference.
+var templateFields = []string{"PodName", "Network", "Scope", "Namespace", "Cluster"}
+
+// apiFields maps the template fields to the fields of the DNSService API entries.
+var apiFields = map[string]string{
+	"PodName":   "pod_name",
+	"Network":   "network",
+	"Scope":     "scope",
+	"Namespace": "namespace",
+	"Cluster":   "cluster",
+}
+
+// NameTemplate turns DNSEntry fields into DNS names and back. The name is the Go template
+// rendered with the entry (e.g. "{{.PodName}}.{{.Network}}.{{.Scope}}") followed by the TLD.
+// Fields should be separated by dots so that names can be parsed unambiguously.
+type NameTemplate struct {
+	text    string
+	tmpl    *template.Template
+	tld     string
+	fields  []string
+	pattern *regexp.Regexp
+	// groups maps every capture group of pattern to the field it captures.
+	groups []string
+}
+
+// NewNameTemplate validates text and tld and compiles them into a NameTemplate.
+func NewNameTemplate(text, tld string) (*NameTemplate, error) {
+	tld = strings.Trim(tld, ".")
+	for _, label := range strings.Split(tld, ".") {
+		if errs := isDNS1123Label(label); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid TLD %q: %s", tld, strings.Join(errs, "; "))
+		}
+	}
+
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %v", text, err)
+	}
+
+	// Render the template with a marker in every field to find out which fields it uses and where.
+	markers := DNSEntry{}
+	for _, field := range templateFields {
+		setField(&markers, field, "\x00"+field+"\x00")
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, markers); err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %v", text, err)
+	}
+
+	nt := &NameTemplate{text: text, tmpl: tmpl, tld: tld}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	seen := make(map[string]bool)
+	for i, part := range strings.Split(rendered.String(), "\x00") {
+		if i%2 == 0 {
+			pattern.WriteString(regexp.QuoteMeta(part))
+			continue
+		}
+		pattern.WriteString("([^.]+)")
+		nt.groups = append(nt.groups, part)
+		if !seen[part] {
+			seen[part] = true
+			nt.fields = append(nt.fields, part)
+		}
+	}
+	if len(nt.fields) == 0 {
+		return nil, fmt.Errorf("invalid name template %q: it must reference at least one field of %v", text, templateFields)
+	}
+	pattern.WriteString(regexp.QuoteMeta("." + tld))
+	pattern.WriteString("$")
+	nt.pattern = regexp.MustCompile(pattern.String())
+	return nt, nil
+}
+
+// Fields returns the DNSEntry fields the template references, in order of appearance.
+func (nt *NameTemplate) Fields() []string {
+	return append([]string(nil), nt.fields...)
+}
+
+// TLD returns the domain every generated name belongs to.
+func (nt *NameTemplate) TLD() string {
+	return nt.tld
+}
+
+// GenerateKey renders the DNS name of dnsEntry. Every field referenced by the template must be set
+// and be a valid RFC 1123 label, and the resulting name must be a valid RFC 1123 subdomain.
+func (nt *NameTemplate) GenerateKey(dnsEntry DNSEntry) (string, error) {
+	var missing []string
+	for _, field := range nt.fields {
+		if getField(dnsEntry, field) == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return "", invalidArgument(apiFields[missing[0]], "input entry has fields missing. Fields %v must be filled, received: %v", missing, dnsEntry)
+	}
+	for _, field := range nt.fields {
+		value := getField(dnsEntry, field)
+		if errs := isDNS1123Label(value); len(errs) > 0 {
+			return "", invalidArgument(apiFields[field], "invalid %s %q: %s", field, value, strings.Join(errs, "; "))
+		}
+	}
+
+	var rendered strings.Builder
+	if err := nt.tmpl.Execute(&rendered, dnsEntry); err != nil {
+		return "", fmt.Errorf("could not render name template %q: %v", nt.text, err)
+	}
+	key := rendered.String() + "." + nt.tld
+
+	if len(key) > dns1123SubdomainMaxLength {
+		return "", invalidArgument("", "name %q is %d characters long, must be no more than %d", key, len(key), dns1123SubdomainMaxLength)
+	}
+	for _, label := range strings.Split(key, ".") {
+		if errs := isDNS1123Label(label); len(errs) > 0 {
+			return "", invalidArgument("", "name %q has an invalid label %q: %s", key, label, strings.Join(errs, "; "))
+		}
+	}
+	return key, nil
+}
+
+// ParseKey decodes a name generated by GenerateKey back into the DNSEntry fields the template
+// references. It returns an error for names that do not follow the template.
+func (nt *NameTemplate) ParseKey(key string) (DNSEntry, error) {
+	key = strings.TrimSuffix(key, ".")
+	if !strings.HasSuffix(key, "."+nt.tld) {
+		return DNSEntry{}, fmt.Errorf("key %q is not an L2SM name", key)
+	}
+	for _, label := range strings.Split(key, ".") {
+		if label == "" {
+			return DNSEntry{}, fmt.Errorf("key %q has empty labels", key)
+		}
+	}
+
+	match := nt.pattern.FindStringSubmatch(key)
+	if match == nil {
+		return DNSEntry{}, fmt.Errorf("key %q does not match the name template %q", key, nt.text)
+	}
+	dnsEntry := DNSEntry{}
+	for i, field := range nt.groups {
+		if previous := getField(dnsEntry, field); previous != "" && previous != match[i+1] {
+			return DNSEntry{}, fmt.Errorf("key %q does not match the name template %q", key, nt.text)
+		}
+		setField(&dnsEntry, field, match[i+1])
+	}
+	return dnsEntry, nil
+}
+
+func getField(dnsEntry DNSEntry, field string) string {
+	switch field {
+	case "PodName":
+		return dnsEntry.PodName
+	case "Network":
+		return dnsEntry.Network
+	case "Scope":
+		return dnsEntry.Scope
+	case "Namespace":
+		return dnsEntry.Namespace
+	case "Cluster":
+		return dnsEntry.Cluster
+	}
+	return ""
+}
+
+func setField(dnsEntry *DNSEntry, field, value string) {
+	switch field {
+	case "PodName":
+		dnsEntry.PodName = value
+	case "Network":
+		dnsEntry.Network = value
+	case "Scope":
+		dnsEntry.Scope = value
+	case "Namespace":
+		dnsEntry.Namespace = value
+	case "Cluster":
+		dnsEntry.Cluster = value
+	}
+}
+
+// The limits and format of RFC 1123 labels and names, as validated by Kubernetes.
+const (
+	dns1123LabelMaxLength     = 63
+	dns1123SubdomainMaxLength = 253
+	dns1123LabelFmt           = "[a-z0-9]([-a-z0-9]*[a-z0-9])?"
+)
+
+var (
+	dns1123LabelRegexp     = regexp.MustCompile("^" + dns1123LabelFmt + "$")
+	dns1123SubdomainRegexp = regexp.MustCompile("^" + dns1123LabelFmt + "(\\." + dns1123LabelFmt + ")*$")
+)
+
+// isDNS1123Label returns why value is not a valid RFC 1123 label, if it is not.
+func isDNS1123Label(value string) []string {
+	var errs []string
+	if len(value) > dns1123LabelMaxLength {
+		errs = append(errs, fmt.Sprintf("must be no more than %d characters", dns1123LabelMaxLength))
+	}
+	if !dns1123LabelRegexp.MatchString(value) {
+		if dns1123SubdomainRegexp.MatchString(value) {
+			// A valid name that is not a valid label has dots.
+			errs = append(errs, "must not contain dots")
+		} else {
+			errs = append(errs, "a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', "+
+				"and must start and end with an alphanumeric character (e.g. 'my-name', or '123-abc', regex used for validation is '"+dns1123LabelFmt+"')")
+		}
+	}
+	return errs
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2smzone
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Record types that can be registered besides the A/AAAA records of the entries.
+const (
+	RecordTypeCNAME = "CNAME"
+	RecordTypeSRV   = "SRV"
+	RecordTypeTXT   = "TXT"
+)
+
+// DefaultRecordTTL is the TTL of records registered without one, the same as the hosts plugin's.
+const DefaultRecordTTL = 3600
+
+// Record is a CNAME, SRV or TXT record. Name is the owner name, which must be under the L2SM TLD.
+type Record struct {
+	Name string
+	Type string
+	// TTL in seconds. Zero means the default of one hour.
+	TTL uint32
+	// Target of CNAME and SRV records.
+	Target string
+	// Priority, Weight and Port of SRV records.
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	// Text of TXT records, one string per element.
+	Text []string
+}
+
+// RR converts r into a resource record of the zone of tld, e.g. "l2sm", validating it.
+func (r Record) RR(tld string) (dns.RR, error) {
+	origin := dns.Fqdn(strings.ToLower(tld))
+	name := dns.Fqdn(strings.ToLower(r.Name))
+	if _, ok := dns.IsDomainName(name); !ok {
+		return nil, invalidArgument("name", "invalid record name %q", r.Name)
+	}
+	if !dns.IsSubDomain(origin, name) || dns.CountLabel(name) <= dns.CountLabel(origin) {
+		return nil, invalidArgument("name", "record name %q is not under the %q zone", r.Name, strings.TrimSuffix(tld, "."))
+	}
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = DefaultRecordTTL
+	}
+	hdr := dns.RR_Header{Name: name, Class: dns.ClassINET, Ttl: ttl}
+
+	switch r.Type {
+	case RecordTypeCNAME, RecordTypeSRV:
+		target := dns.Fqdn(strings.ToLower(r.Target))
+		if _, ok := dns.IsDomainName(target); !ok || r.Target == "" {
+			return nil, invalidArgument("target", "invalid %s target %q", r.Type, r.Target)
+		}
+		if r.Type == RecordTypeCNAME {
+			hdr.Rrtype = dns.TypeCNAME
+			return &dns.CNAME{Hdr: hdr, Target: target}, nil
+		}
+		hdr.Rrtype = dns.TypeSRV
+		return &dns.SRV{Hdr: hdr, Priority: r.Priority, Weight: r.Weight, Port: r.Port, Target: target}, nil
+	case RecordTypeTXT:
+		if len(r.Text) == 0 {
+			return nil, invalidArgument("text", "TXT record %q has no text", r.Name)
+		}
+		hdr.Rrtype = dns.TypeTXT
+		return &dns.TXT{Hdr: hdr, Txt: r.Text}, nil
+	default:
+		return nil, invalidArgument("type", "unsupported record type %q", r.Type)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2smzone
+
+import (
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// hostsTTL is the TTL of the A, AAAA and PTR records, the same as the CoreDNS hosts plugin's.
+const hostsTTL = 3600
+
+// maxCNAMEChain bounds how many CNAME records are followed within the zone to answer a query.
+const maxCNAMEChain = 8
+
+// Zone is the in-memory L2SM zone: the A and AAAA records of the entries, their reverse PTR records,
+// and the CNAME, SRV and TXT records. It is safe for concurrent use.
+type Zone struct {
+	origin string
+
+	mu sync.RWMutex
+	// addresses holds the IP addresses of every registered name, and names the names of every
+	// reverse name, all fully qualified and in lower case.
+	addresses map[string]map[string]struct{}
+	names     map[string]map[string]struct{}
+	records   map[string][]dns.RR
+	serial    uint32
+}
+
+// NewZone returns an empty zone for tld, e.g. "l2sm".
+func NewZone(tld string) *Zone {
+	return &Zone{
+		origin:    dns.Fqdn(strings.ToLower(tld)),
+		addresses: make(map[string]map[string]struct{}),
+		names:     make(map[string]map[string]struct{}),
+		records:   make(map[string][]dns.RR),
+	}
+}
+
+// SetHosts replaces the A and AAAA records with the ip -> []names records.
+func (z *Zone) SetHosts(records map[string][]string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.addresses = make(map[string]map[string]struct{})
+	z.names = make(map[string]map[string]struct{})
+	for ip, names := range records {
+		for _, name := range names {
+			z.addHostLocked(ip, name)
+		}
+	}
+	z.serial++
+}
+
+// AddHost registers name for ip.
+func (z *Zone) AddHost(ip, name string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.addHostLocked(ip, name)
+	z.serial++
+}
+
+// RemoveHost unregisters name from ip.
+func (z *Zone) RemoveHost(ip, name string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return
+	}
+	name = dns.Fqdn(strings.ToLower(name))
+	reverse, _ := dns.ReverseAddr(addr.String())
+	delete(z.addresses[name], addr.String())
+	if len(z.addresses[name]) == 0 {
+		delete(z.addresses, name)
+	}
+	delete(z.names[reverse], name)
+	if len(z.names[reverse]) == 0 {
+		delete(z.names, reverse)
+	}
+	z.serial++
+}
+
+func (z *Zone) addHostLocked(ip, name string) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return
+	}
+	name = dns.Fqdn(strings.ToLower(name))
+	reverse, err := dns.ReverseAddr(addr.String())
+	if err != nil {
+		return
+	}
+	if z.addresses[name] == nil {
+		z.addresses[name] = make(map[string]struct{})
+	}
+	z.addresses[name][addr.String()] = struct{}{}
+	if z.names[reverse] == nil {
+		z.names[reverse] = make(map[string]struct{})
+	}
+	z.names[reverse][name] = struct{}{}
+}
+
+// SetRecords replaces the CNAME, SRV and TXT records. Invalid records are skipped.
+func (z *Zone) SetRecords(list []Record) {
+	records := make(map[string][]dns.RR, len(list))
+	for _, record := range list {
+		rr, err := record.RR(z.origin)
+		if err != nil {
+			log.Printf("skipping DNS record %s %s: %v", record.Type, record.Name, err)
+			continue
+		}
+		records[rr.Header().Name] = append(records[rr.Header().Name], rr)
+	}
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.records = records
+	z.serial++
+}
+
+// Matches returns whether the zone answers for name: it is in the zone, or is the reverse name of a
+// registered address.
+func (z *Zone) Matches(name string) bool {
+	name = strings.ToLower(dns.Fqdn(name))
+	if dns.IsSubDomain(z.origin, name) {
+		return true
+	}
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+	_, ok := z.names[name]
+	return ok
+}
+
+// Answer builds the authoritative answer to req, whose question Matches the zone.
+func (z *Zone) Answer(req *dns.Msg) *dns.Msg {
+	reply := new(dns.Msg)
+	reply.SetReply(req)
+	reply.Authoritative = true
+	if len(req.Question) != 1 {
+		reply.Rcode = dns.RcodeFormatError
+		return reply
+	}
+	name := strings.ToLower(req.Question[0].Name)
+	qtype := req.Question[0].Qtype
+
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	if names, ok := z.names[name]; ok {
+		if qtype == dns.TypePTR || qtype == dns.TypeANY {
+			for _, target := range sortedKeys(names) {
+				reply.Answer = append(reply.Answer, &dns.PTR{
+					Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: hostsTTL},
+					Ptr: target,
+				})
+			}
+		}
+		return reply
+	}
+
+	if name == z.origin && (qtype == dns.TypeSOA || qtype == dns.TypeANY) {
+		reply.Answer = append(reply.Answer, z.soaLocked())
+	}
+	current := name
+	for i := 0; i < maxCNAMEChain && current != ""; i++ {
+		answer, next := z.lookupLocked(current, qtype)
+		reply.Answer = append(reply.Answer, answer...)
+		current = next
+	}
+
+	if len(reply.Answer) == 0 {
+		if !z.existsLocked(name) {
+			reply.Rcode = dns.RcodeNameError
+		}
+		reply.Ns = append(reply.Ns, z.soaLocked())
+	}
+	return reply
+}
+
+// lookupLocked returns the records of name for qtype, and the target of its CNAME record, if any
+// and in the zone, which the answer continues with.
+func (z *Zone) lookupLocked(name string, qtype uint16) ([]dns.RR, string) {
+	var answer []dns.RR
+	for _, rr := range z.records[name] {
+		if rr.Header().Rrtype == dns.TypeCNAME && qtype != dns.TypeCNAME {
+			target := rr.(*dns.CNAME).Target
+			if dns.IsSubDomain(z.origin, target) {
+				return []dns.RR{dns.Copy(rr)}, target
+			}
+			return []dns.RR{dns.Copy(rr)}, ""
+		}
+		if rr.Header().Rrtype == qtype || qtype == dns.TypeANY {
+			answer = append(answer, dns.Copy(rr))
+		}
+	}
+	for _, ip := range sortedKeys(z.addresses[name]) {
+		addr := net.ParseIP(ip)
+		if v4 := addr.To4(); v4 != nil && (qtype == dns.TypeA || qtype == dns.TypeANY) {
+			answer = append(answer, &dns.A{
+				Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: hostsTTL},
+				A:   v4,
+			})
+		} else if v4 == nil && (qtype == dns.TypeAAAA || qtype == dns.TypeANY) {
+			answer = append(answer, &dns.AAAA{
+				Hdr:  dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: hostsTTL},
+				AAAA: addr,
+			})
+		}
+	}
+	return answer, ""
+}
+
+// existsLocked returns whether name has records, or is an empty non-terminal of names that do.
+func (z *Zone) existsLocked(name string) bool {
+	if _, ok := z.addresses[name]; ok {
+		return true
+	}
+	if _, ok := z.records[name]; ok {
+		return true
+	}
+	if name == z.origin {
+		return true
+	}
+	suffix := "." + name
+	for owner := range z.addresses {
+		if strings.HasSuffix(owner, suffix) {
+			return true
+		}
+	}
+	for owner := range z.records {
+		if strings.HasSuffix(owner, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (z *Zone) soaLocked() dns.RR {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: z.origin, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 5},
+		Ns:      "ns.dns." + z.origin,
+		Mbox:    "hostmaster." + z.origin,
+		Serial:  z.serial,
+		Refresh: 7200,
+		Retry:   1800,
+		Expire:  86400,
+		Minttl:  5,
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
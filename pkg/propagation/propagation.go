@@ -0,0 +1,113 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package propagation waits for the changes written to the CoreDNS configuration to be served.
+// A written entry only resolves once the kubelet syncs the ConfigMap volume and CoreDNS reloads it,
+// which the write itself does not wait for.
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// queryTimeout bounds every query to the endpoint.
+const queryTimeout = 2 * time.Second
+
+// defaultInterval is how long to wait between queries if Interval is unset.
+const defaultInterval = 500 * time.Millisecond
+
+// Waiter queries a DNS endpoint until names resolve to the expected addresses.
+type Waiter struct {
+	// Endpoint is the "host:port" of the CoreDNS server that serves the entries.
+	Endpoint string
+	// Interval is how long to wait between queries. If zero or negative, defaultInterval is used.
+	Interval time.Duration
+}
+
+// WaitForAddresses queries the endpoint until name resolves to every one of addresses, and returns
+// the error of ctx if it is done first. Other addresses of name do not matter.
+func (w *Waiter) WaitForAddresses(ctx context.Context, name string, addresses ...string) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("no addresses to wait for")
+	}
+	for _, address := range addresses {
+		if net.ParseIP(address) == nil {
+			return fmt.Errorf("invalid IP address %q", address)
+		}
+	}
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ok, err := w.resolves(ctx, name, addresses)
+		if ok {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%s does not resolve on %s: %w (last error: %v)", name, w.Endpoint, ctx.Err(), err)
+			}
+			return fmt.Errorf("%s does not resolve on %s: %w", name, w.Endpoint, ctx.Err())
+		}
+	}
+}
+
+// resolves returns whether name resolves to every one of addresses on the endpoint.
+func (w *Waiter) resolves(ctx context.Context, name string, addresses []string) (bool, error) {
+	want := map[uint16]map[string]bool{}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		qtype := dns.TypeAAAA
+		if ip.To4() != nil {
+			qtype = dns.TypeA
+		}
+		if want[qtype] == nil {
+			want[qtype] = map[string]bool{}
+		}
+		want[qtype][ip.String()] = true
+	}
+
+	client := &dns.Client{Timeout: queryTimeout}
+	for qtype, ips := range want {
+		req := new(dns.Msg)
+		req.SetQuestion(dns.Fqdn(name), qtype)
+		reply, _, err := client.ExchangeContext(ctx, req, w.Endpoint)
+		if err != nil {
+			return false, err
+		}
+		for _, rr := range reply.Answer {
+			switch rr := rr.(type) {
+			case *dns.A:
+				delete(ips, rr.A.String())
+			case *dns.AAAA:
+				delete(ips, rr.AAAA.String())
+			}
+		}
+		if len(ips) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package responder serves the L2SM zone over DNS straight from the state of a DNSManager, without
+// waiting for CoreDNS to pick up the ConfigMap.
+package responder
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smzone"
+	"github.com/miekg/dns"
+)
+
+// defaultRefreshInterval is how often the records and servers are re-read if RefreshInterval is unset.
+const defaultRefreshInterval = 10 * time.Second
+
+// forwardTimeout bounds how long a forwarded query waits for each upstream.
+const forwardTimeout = 2 * time.Second
+
+// Responder is an authoritative DNS server for the L2SM zone. The A and AAAA records are kept up to
+// date through DNSManager.WatchDNSRecords, so a registered entry resolves as soon as it is written.
+// The CNAME, SRV and TXT records and the forward servers are re-read every RefreshInterval. The
+// addresses of the entries also resolve in reverse. Queries for the zones of the forward servers are
+// forwarded to their upstreams, and any other query to Upstreams.
+type Responder struct {
+	DNSManager configmapmanager.DNSManager
+	// Addr is the address served over both UDP and TCP, e.g. ":1053".
+	Addr string
+	// TLD is the zone served, e.g. "l2sm".
+	TLD string
+	// Upstreams are the "host:port" servers the queries outside every known zone are forwarded to.
+	// Without upstreams, those queries are refused.
+	Upstreams       []string
+	RefreshInterval time.Duration
+
+	zone *l2smzone.Zone
+	mu   sync.RWMutex
+	// forwards maps the zone of every forward server to its configuration.
+	forwards map[string]configmapmanager.ForwardConfig
+}
+
+// Start loads the records, serves them on Addr and keeps them up to date until ctx is done.
+func (r *Responder) Start(ctx context.Context) error {
+	records, _, events, err := r.DNSManager.WatchDNSRecords(ctx)
+	if err != nil {
+		return fmt.Errorf("could not watch DNS records: %w", err)
+	}
+	r.zone = l2smzone.NewZone(r.TLD)
+	r.zone.SetHosts(records)
+	if err := r.refresh(ctx); err != nil {
+		return err
+	}
+
+	udp, err := net.ListenPacket("udp", r.Addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s/udp: %w", r.Addr, err)
+	}
+	tcp, err := net.Listen("tcp", r.Addr)
+	if err != nil {
+		udp.Close()
+		return fmt.Errorf("could not listen on %s/tcp: %w", r.Addr, err)
+	}
+	servers := []*dns.Server{
+		{PacketConn: udp, Handler: r},
+		{Listener: tcp, Handler: r},
+	}
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *dns.Server) {
+			errs <- server.ActivateAndServe()
+		}(server)
+	}
+	defer func() {
+		for _, server := range servers {
+			_ = server.Shutdown()
+		}
+	}()
+
+	interval := r.RefreshInterval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return fmt.Errorf("DNS responder stopped: %w", err)
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Type == configmapmanager.RecordAdded {
+				r.zone.AddHost(event.IPAddress, event.DNSName)
+			} else {
+				r.zone.RemoveHost(event.IPAddress, event.DNSName)
+			}
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				log.Printf("could not refresh DNS responder: %v", err)
+			}
+		}
+	}
+}
+
+// refresh re-reads the CNAME, SRV and TXT records and the forward servers.
+func (r *Responder) refresh(ctx context.Context) error {
+	records, err := r.DNSManager.ListRecords(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list DNS records: %w", err)
+	}
+	r.zone.SetRecords(records)
+
+	servers, err := r.DNSManager.ListServers(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list forward servers: %w", err)
+	}
+	forwards := make(map[string]configmapmanager.ForwardConfig, len(servers))
+	for _, server := range servers {
+		zone := server.DomPort
+		if host, _, err := net.SplitHostPort(zone); err == nil {
+			zone = host
+		}
+		forwards[dns.Fqdn(strings.ToLower(zone))] = server.Forward
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forwards = forwards
+	return nil
+}
+
+// ServeDNS answers a query from the L2SM zone, or forwards it.
+func (r *Responder) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	if len(req.Question) != 1 {
+		reply := new(dns.Msg)
+		reply.SetRcode(req, dns.RcodeFormatError)
+		_ = w.WriteMsg(reply)
+		return
+	}
+	q := req.Question[0]
+	if r.zone.Matches(q.Name) {
+		_ = w.WriteMsg(r.zone.Answer(req))
+		return
+	}
+
+	r.mu.RLock()
+	upstreams, forward := r.upstreamsLocked(strings.ToLower(q.Name))
+	r.mu.RUnlock()
+
+	if len(upstreams.Upstreams) == 0 {
+		reply := new(dns.Msg)
+		reply.SetRcode(req, dns.RcodeRefused)
+		_ = w.WriteMsg(reply)
+		return
+	}
+	reply, err := r.forward(req, w.RemoteAddr().Network(), upstreams)
+	if err != nil {
+		log.Printf("could not forward %s query for %s (%s): %v", dns.TypeToString[q.Qtype], q.Name, forward, err)
+		reply = new(dns.Msg)
+		reply.SetRcode(req, dns.RcodeServerFailure)
+	}
+	_ = w.WriteMsg(reply)
+}
+
+// upstreamsLocked returns the forward configuration for name: that of the forward server of the
+// longest zone name is in, or Upstreams. It also returns the zone, for logging.
+func (r *Responder) upstreamsLocked(name string) (configmapmanager.ForwardConfig, string) {
+	best := ""
+	for zone := range r.forwards {
+		if dns.IsSubDomain(zone, name) && dns.CountLabel(zone) > dns.CountLabel(best) {
+			best = zone
+		}
+	}
+	if best != "" {
+		return r.forwards[best], best
+	}
+	return configmapmanager.ForwardConfig{Upstreams: r.Upstreams}, "."
+}
+
+// forward sends req to the upstreams in order, over the network the query came from, and returns
+// the first response.
+func (r *Responder) forward(req *dns.Msg, network string, cfg configmapmanager.ForwardConfig) (*dns.Msg, error) {
+	client := &dns.Client{Net: "udp", Timeout: forwardTimeout}
+	if network == "tcp" {
+		client.Net = "tcp"
+	}
+	if cfg.TLS {
+		client.Net = "tcp-tls"
+		client.TLSConfig = &tls.Config{ServerName: cfg.TLSServerName}
+	}
+
+	var lastErr error
+	for _, upstream := range cfg.Upstreams {
+		reply, _, err := client.Exchange(req, strings.TrimPrefix(upstream, "tls://"))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return reply, nil
+	}
+	return nil, lastErr
+}
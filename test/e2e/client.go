@@ -17,25 +17,34 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func main() {
 	// Command-line flags.
 	testAddEntry := flag.Bool("test-add-entry", false, "Simulate adding a DNS entry")
 	testAddServer := flag.Bool("test-add-server", false, "Simulate adding a server")
+	testRemoveServer := flag.Bool("test-remove-server", false, "Remove the server of the config file")
+	testListServers := flag.Bool("test-list-servers", false, "List the forward servers")
 	testDeleteEntry := flag.Bool("test-delete-entry", false, "Simulate deleting a DNS entry")
+	testRenewEntry := flag.Bool("test-renew-entry", false, "Renew the lease of a DNS entry (requires --ttl)")
+	testWatchEntries := flag.Bool("test-watch-entries", false, "Watch DNS entry changes until interrupted (filtered by --network and --scope flags)")
+	testListEntries := flag.Bool("test-list-entries", false, "List the registered DNS entries (filtered by --network and --scope flags)")
 
 	configPath := flag.String("config", "./config.yaml", "Path to YAML config file")
 	// Allow overriding default DNS entry parameters from config.
 	podName := flag.String("pod", "", "Pod name for the DNS entry")
-	ipAddress := flag.String("ip", "", "IP address for the DNS entry")
+	ipAddress := flag.String("ip", "", "IP address for the DNS entry (comma-separated for dual-stack pods)")
 	network := flag.String("network", "", "Network for the DNS entry")
 	scope := flag.String("scope", "", "Scope for the DNS entry (default: global)")
+	ttl := flag.Duration("ttl", 0, "Lease for the DNS entry, e.g. 5m (default: no lease)")
+	wait := flag.Duration("wait", 0, "Wait up to this long for the added DNS entry to resolve, e.g. 2m (default: do not wait)")
 
 	flag.Parse()
 
@@ -50,7 +59,8 @@ func main() {
 		cfg.DNS.PodName = *podName
 	}
 	if *ipAddress != "" {
-		cfg.DNS.IpAddress = *ipAddress
+		addresses := strings.Split(*ipAddress, ",")
+		cfg.DNS.IpAddress, cfg.DNS.IpAddresses = addresses[0], addresses[1:]
 	}
 	if *network != "" {
 		cfg.DNS.Network = *network
@@ -77,14 +87,21 @@ func main() {
 		fmt.Println("Sending AddEntry request...")
 		req := &dns.AddEntryRequest{
 			Entry: &dns.DNSEntry{
-				PodName:   cfg.DNS.PodName,
-				IpAddress: cfg.DNS.IpAddress,
-				Network:   cfg.DNS.Network,
-				Scope:     cfg.DNS.Scope,
+				PodName:     cfg.DNS.PodName,
+				IpAddress:   cfg.DNS.IpAddress,
+				IpAddresses: cfg.DNS.IpAddresses,
+				Network:     cfg.DNS.Network,
+				Scope:       cfg.DNS.Scope,
 			},
 		}
+		if *ttl > 0 {
+			req.Ttl = durationpb.New(*ttl)
+		}
+		if *wait > 0 {
+			req.WaitTimeout = durationpb.New(*wait)
+		}
 		// Wrap the call in a context with timeout.
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second+*wait)
 		defer cancel()
 		resp, err := client.AddEntry(ctx, req)
 		if err != nil {
@@ -97,10 +114,11 @@ func main() {
 		fmt.Println("Sending DeleteEntry request...")
 		req := &dns.DeleteEntryRequest{
 			Entry: &dns.DNSEntry{
-				PodName:   cfg.DNS.PodName,
-				IpAddress: cfg.DNS.IpAddress,
-				Network:   cfg.DNS.Network,
-				Scope:     cfg.DNS.Scope,
+				PodName:     cfg.DNS.PodName,
+				IpAddress:   cfg.DNS.IpAddress,
+				IpAddresses: cfg.DNS.IpAddresses,
+				Network:     cfg.DNS.Network,
+				Scope:       cfg.DNS.Scope,
 			},
 		}
 		// Wrap the call in a context with timeout.
@@ -112,13 +130,36 @@ func main() {
 		}
 		fmt.Printf("AddEntry response: %s\n", resp.GetMessage())
 	}
+	if *testRenewEntry {
+		fmt.Println("Sending RenewEntry request...")
+		req := &dns.RenewEntryRequest{
+			Entry: &dns.DNSEntry{
+				PodName: cfg.DNS.PodName,
+				Network: cfg.DNS.Network,
+				Scope:   cfg.DNS.Scope,
+			},
+			Ttl: durationpb.New(*ttl),
+		}
+		// Wrap the call in a context with timeout.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		resp, err := client.RenewEntry(ctx, req)
+		if err != nil {
+			log.Fatalf("Failed to renew DNS entry: %v", err)
+		}
+		fmt.Printf("RenewEntry response: %s\n", resp.GetMessage())
+	}
 	if *testAddServer {
 		fmt.Println("Sending AddServer request...")
 		req := &dns.AddServerRequest{
 			Server: &dns.Server{
-				DomPort:      cfg.Server.DomPort,
-				ServerDomain: cfg.Server.ServerDomain,
-				ServerPort:   cfg.Server.ServerPort,
+				DomPort:       cfg.Server.DomPort,
+				ServerDomain:  cfg.Server.ServerDomain,
+				ServerPort:    cfg.Server.ServerPort,
+				Upstreams:     cfg.Server.Upstreams,
+				Policy:        dns.ForwardPolicy(dns.ForwardPolicy_value["FORWARD_POLICY_"+strings.ToUpper(cfg.Server.Policy)]),
+				Tls:           cfg.Server.TLS,
+				TlsServername: cfg.Server.TLSServerName,
 			},
 		}
 		// Wrap the call in a context with timeout.
@@ -130,4 +171,64 @@ func main() {
 		}
 		fmt.Printf("AddEntry response: %s\n", resp.GetMessage())
 	}
+
+	if *testRemoveServer {
+		fmt.Println("Sending RemoveServer request...")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		resp, err := client.RemoveServer(ctx, &dns.RemoveServerRequest{DomPort: cfg.Server.DomPort})
+		if err != nil {
+			log.Fatalf("Failed to remove DNS server: %v", err)
+		}
+		fmt.Printf("RemoveServer response: %s\n", resp.GetMessage())
+	}
+
+	if *testListServers {
+		fmt.Println("Sending ListServers request...")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		resp, err := client.ListServers(ctx, &dns.ListServersRequest{})
+		if err != nil {
+			log.Fatalf("Failed to list DNS servers: %v", err)
+		}
+		for _, server := range resp.GetServers() {
+			fmt.Printf("%s -> %s:%s\n", server.GetDomPort(), server.GetServerDomain(), server.GetServerPort())
+		}
+	}
+
+	if *testListEntries {
+		fmt.Println("Sending ListEntries requests...")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req := &dns.ListEntriesRequest{Network: *network, Scope: *scope}
+		for {
+			resp, err := client.ListEntries(ctx, req)
+			if err != nil {
+				log.Fatalf("Failed to list DNS entries: %v", err)
+			}
+			for _, entry := range resp.GetEntries() {
+				fmt.Printf("%s.%s.%s -> %s\n", entry.GetPodName(), entry.GetNetwork(), entry.GetScope(), strings.Join(entry.GetIpAddresses(), ", "))
+			}
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = resp.GetNextPageToken()
+		}
+	}
+
+	if *testWatchEntries {
+		fmt.Println("Sending WatchEntries request...")
+		stream, err := client.WatchEntries(context.Background(), &dns.WatchEntriesRequest{Network: *network, Scope: *scope})
+		if err != nil {
+			log.Fatalf("Failed to watch DNS entries: %v", err)
+		}
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				log.Fatalf("Watch stream ended: %v", err)
+			}
+			entry := event.GetEntry()
+			fmt.Printf("[%s] %s %s.%s.%s -> %s\n", event.GetResourceVersion(), event.GetType(), entry.GetPodName(), entry.GetNetwork(), entry.GetScope(), entry.GetIpAddress())
+		}
+	}
 }
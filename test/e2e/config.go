@@ -29,16 +29,21 @@ type Config struct {
 
 // DNSEntryConfig holds the default DNS entry parameters.
 type DNSEntryConfig struct {
-	PodName   string `yaml:"podName"`
-	IpAddress string `yaml:"ipAddress"`
-	Network   string `yaml:"network"`
-	Scope     string `yaml:"scope"`
+	PodName     string   `yaml:"podName"`
+	IpAddress   string   `yaml:"ipAddress"`
+	IpAddresses []string `yaml:"ipAddresses"`
+	Network     string   `yaml:"network"`
+	Scope       string   `yaml:"scope"`
 }
 
 type DNSServerConfig struct {
-	DomPort      string `yaml:"domPort"`
-	ServerDomain string `yaml:"serverDomain"`
-	ServerPort   string `yaml:"serverPort"`
+	DomPort       string   `yaml:"domPort"`
+	ServerDomain  string   `yaml:"serverDomain"`
+	ServerPort    string   `yaml:"serverPort"`
+	Upstreams     []string `yaml:"upstreams"`
+	Policy        string   `yaml:"policy"`
+	TLS           bool     `yaml:"tls"`
+	TLSServerName string   `yaml:"tlsServerName"`
 }
 
 // LoadConfig reads a YAML configuration file and unmarshals it into a Config struct.
@@ -0,0 +1,221 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// ----------------------------------------------
+// Batching
+// ----------------------------------------------
+func newCountingDNSManager(t *testing.T, updates *int32, objs ...client.Object) configmapmanager.DNSManager {
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				atomic.AddInt32(updates, 1)
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	return mgr
+}
+
+func TestBatchingDNSManagerCoalescesWrites(t *testing.T) {
+	const writers = 50
+
+	var updates int32
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.1.1 stale.net1.global.l2sm
+  }
+}`)
+	mgr := configmapmanager.NewBatchingDNSManager(newCountingDNSManager(t, &updates, cm), time.Second, writers+1)
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers+2)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = mgr.AddDNSEntry(context.Background(), fmt.Sprintf("pod-%d.net1.global.l2sm", i), fmt.Sprintf("10.0.0.%d", i+1))
+		}(i)
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[writers] = mgr.RemoveDNSEntry(context.Background(), "stale.net1.global.l2sm", "10.0.1.1")
+	}()
+	go func() {
+		defer wg.Done()
+		errs[writers+1] = mgr.AddDNSEntry(context.Background(), "bad.net1.global.l2sm", "NOT_AN_IP")
+	}()
+	wg.Wait()
+
+	for i := 0; i < writers+1; i++ {
+		require.NoError(t, errs[i])
+	}
+	require.Error(t, errs[writers+1])
+	require.Contains(t, errs[writers+1].Error(), "invalid IP address")
+	require.Equal(t, int32(1), atomic.LoadInt32(&updates))
+
+	records, err := mgr.ListDNSRecords(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, writers)
+	require.NotContains(t, records, "10.0.1.1")
+}
+
+func TestBatchingDNSManagerFlushesFullBatch(t *testing.T) {
+	var updates int32
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	// With a one-entry batch the window never expires: every mutation is written right away.
+	mgr := configmapmanager.NewBatchingDNSManager(newCountingDNSManager(t, &updates, cm), time.Hour, 1)
+
+	require.NoError(t, mgr.AddDNSEntry(context.Background(), "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.RemoveDNSEntry(context.Background(), "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.Equal(t, int32(2), atomic.LoadInt32(&updates))
+}
+
+func TestBatchingDNSManagerIsolatesFailingCall(t *testing.T) {
+	const writers = 6
+
+	// A single call with many addresses takes the ConfigMap over its size limit.
+	t.Setenv("DNS_CONFIGMAP_MAX_SIZE", "1000")
+	var updates int32
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := configmapmanager.NewBatchingDNSManager(newCountingDNSManager(t, &updates, cm), time.Hour, writers+1)
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers+1)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = mgr.AddDNSEntry(context.Background(), fmt.Sprintf("pod-%d.net1.global.l2sm", i), fmt.Sprintf("10.0.0.%d", i+1))
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var addresses []string
+		for i := 0; i < 40; i++ {
+			addresses = append(addresses, fmt.Sprintf("10.0.1.%d", i+1))
+		}
+		errs[writers] = mgr.AddDNSEntry(context.Background(), "big.net1.global.l2sm", addresses...)
+	}()
+	wg.Wait()
+
+	// Only the call at fault fails; the others are written without it.
+	for i := 0; i < writers; i++ {
+		require.NoError(t, errs[i])
+	}
+	require.ErrorIs(t, errs[writers], configmapmanager.ErrFailedPrecondition)
+	require.LessOrEqual(t, atomic.LoadInt32(&updates), int32(3))
+
+	records, err := mgr.ListDNSRecords(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, writers)
+	require.NotContains(t, records, "10.0.1.1")
+}
+
+func TestBatchingDNSManagerKeepsOrder(t *testing.T) {
+	var updates int32
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 pod-a.net1.global.l2sm
+  }
+}`)
+	mgr := configmapmanager.NewBatchingDNSManager(newCountingDNSManager(t, &updates, cm), time.Hour, 4)
+	ctx := context.Background()
+
+	// Every kind of mutation joins the same batch, which is written once it holds four changes.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	start := func(i int, call func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = call()
+		}()
+		// Let the call be queued before the next one.
+		time.Sleep(20 * time.Millisecond)
+	}
+	start(0, func() error { return mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm") })
+	start(1, func() error { return mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Minute, "10.0.0.2") })
+	start(2, func() error { return mgr.RenewDNSLease(ctx, "missing.net1.global.l2sm", time.Minute) })
+	start(3, func() error {
+		return mgr.UpdateDNSRecords(ctx, map[string][]string{"10.0.0.3": {"pod-b.net1.global.l2sm"}}, nil)
+	})
+	wg.Wait()
+
+	// Every caller gets the result of its own change.
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], configmapmanager.ErrNotFound)
+	require.NoError(t, errs[3])
+	require.Equal(t, int32(1), atomic.LoadInt32(&updates))
+
+	// The queued removal did not undo the later leased addition.
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.2": {"pod-a.net1.global.l2sm"},
+		"10.0.0.3": {"pod-b.net1.global.l2sm"},
+	}, records)
+	require.Contains(t, leasesOf(t, mgr), "pod-a.net1.global.l2sm")
+}
+
+func TestBatchingDNSManagerExpiresAfterQueuedChanges(t *testing.T) {
+	var updates int32
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := configmapmanager.NewBatchingDNSManager(newCountingDNSManager(t, &updates, cm), time.Hour, 100)
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Minute, "10.0.0.1")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Expiring leases writes the queued addition first, instead of waiting for the window.
+	removed, err := mgr.ExpireDNSLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, removed)
+	require.NoError(t, <-done)
+}
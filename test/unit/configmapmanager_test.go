@@ -16,6 +16,7 @@ package configmapmanager_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
@@ -213,7 +214,7 @@ func TestRemoveDNSRecordsEdgeCases(t *testing.T) {
 			}`,
 			removals:       map[string][]string{"1.2.3.4": {"domain.com"}},
 			expectErr:      true,
-			expectedErrMsg: "could not find inter-domain server",
+			expectedErrMsg: "could not find inter-domain port",
 		},
 		{
 			name: "Missing 'hosts' plugin in valid server block",
@@ -529,3 +530,57 @@ func TestAddServerToConfigMapEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// ----------------------------------------------
+// ParseKey
+// ----------------------------------------------
+func TestParseKeyEdgeCases(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            string
+		expected       configmapmanager.DNSEntry
+		expectErr      bool
+		expectedErrMsg string
+	}{
+		{
+			name:     "Key generated by GenerateKey",
+			key:      "pod-a.net1.global.l2sm",
+			expected: configmapmanager.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global"},
+		},
+		{
+			name:     "Fully qualified key",
+			key:      "pod-a.net1.global.l2sm.",
+			expected: configmapmanager.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global"},
+		},
+		{
+			name:           "Name outside the l2sm domain",
+			key:            "domain.com",
+			expectErr:      true,
+			expectedErrMsg: "is not an L2SM name",
+		},
+		{
+			name:           "Empty label",
+			key:            "pod-a..global.l2sm",
+			expectErr:      true,
+			expectedErrMsg: "has empty labels",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			entry, err := configmapmanager.ParseKey(tc.key)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, entry)
+
+			key, err := configmapmanager.GenerateKey(entry)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimSuffix(tc.key, "."), key)
+		})
+	}
+}
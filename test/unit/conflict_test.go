@@ -0,0 +1,98 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// ----------------------------------------------
+// Concurrent writers
+// ----------------------------------------------
+func TestConcurrentAddDNSEntry(t *testing.T) {
+	const writers = 20
+
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+
+	// Hold every writer's first read until all of them have read the same resourceVersion,
+	// so that all but one of the first updates are guaranteed to conflict.
+	var firstReads sync.WaitGroup
+	firstReads.Add(writers)
+	var reads, conflicts int32
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				err := c.Get(ctx, key, obj, opts...)
+				if atomic.AddInt32(&reads, 1) <= writers {
+					firstReads.Done()
+					firstReads.Wait()
+				}
+				return err
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				err := c.Update(ctx, obj, opts...)
+				if apierrors.IsConflict(err) {
+					atomic.AddInt32(&conflicts, 1)
+				}
+				return err
+			},
+		}).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dnsName := fmt.Sprintf("pod-%d.net1.global.l2sm", i)
+			ipAddress := fmt.Sprintf("10.0.0.%d", i+1)
+			errs <- mgr.AddDNSEntry(context.Background(), dnsName, ipAddress)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, atomic.LoadInt32(&conflicts), int32(writers-1))
+
+	records, err := mgr.ListDNSRecords(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, writers)
+	for i := 0; i < writers; i++ {
+		ipAddress := fmt.Sprintf("10.0.0.%d", i+1)
+		require.Equal(t, []string{fmt.Sprintf("pod-%d.net1.global.l2sm", i)}, records[ipAddress])
+	}
+}
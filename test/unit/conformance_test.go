@@ -0,0 +1,69 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1alpha1"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager/conformance"
+	"github.com/stretchr/testify/require"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// ----------------------------------------------
+// DNSManager conformance
+// ----------------------------------------------
+const emptyCorefile = `.:53 {
+    hosts {
+    }
+}`
+
+func TestConformance(t *testing.T) {
+	backends := map[string]conformance.Factory{
+		"ConfigMap": func(t *testing.T) configmapmanager.DNSManager {
+			return newDNSManager(t, createConfigMap("test-cm", "test-namespace", emptyCorefile))
+		},
+		"File": func(t *testing.T) configmapmanager.DNSManager {
+			return newFileDNSManager(t, t.TempDir(), emptyCorefile)
+		},
+		"Memory": func(t *testing.T) configmapmanager.DNSManager {
+			return configmapmanager.NewMemoryDNSManager("")
+		},
+		"CRD": func(t *testing.T) configmapmanager.DNSManager {
+			scheme := createFakeScheme()
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			fclient := crfake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(createConfigMap("test-cm", "test-namespace", emptyCorefile)).
+				Build()
+			inner, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+			require.NoError(t, err)
+			mgr, err := configmapmanager.NewCRDDNSManager(inner, fclient, "test-namespace", nil)
+			require.NoError(t, err)
+			return mgr
+		},
+		"Batching": func(t *testing.T) configmapmanager.DNSManager {
+			return configmapmanager.NewBatchingDNSManager(configmapmanager.NewMemoryDNSManager(emptyCorefile), 10*time.Millisecond, 100)
+		},
+	}
+	for name, newManager := range backends {
+		t.Run(name, func(t *testing.T) {
+			conformance.Run(t, newManager)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/api/v1alpha1"
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/controller"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// ----------------------------------------------
+// L2SMDNSEntry store
+// ----------------------------------------------
+func TestCRDDNSManager(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.9 manual.ns1.net1.l2sm
+    fallthrough
+  }
+}`)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-a", Namespace: "ns1", UID: "pod-a-uid"}}
+	scheme := createFakeScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	fclient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cm, pod).
+		Build()
+	backend, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Namespace}}.{{.Network}}", "l2sm")
+	require.NoError(t, err)
+	mgr, err := configmapmanager.NewCRDDNSManager(backend, fclient, "test-namespace", names)
+	require.NoError(t, err)
+	reconciler := &controller.EntryReconciler{Client: fclient, DNSManager: backend}
+	ctx := context.Background()
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+
+	// Entries are stored as resources, owned by their pod if it exists.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.ns1.net1.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.ns1.net1.l2sm", "2001:db8::1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.ns2.net1.l2sm", "10.0.0.2"))
+
+	entry := &v1alpha1.L2SMDNSEntry{}
+	require.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod-a.ns1.net1.l2sm"}, entry))
+	require.Equal(t, "pod-a.ns1.net1.l2sm", entry.Spec.Hostname)
+	require.Equal(t, []string{"10.0.0.1", "2001:db8::1"}, entry.Spec.IPAddresses)
+	require.Len(t, entry.OwnerReferences, 1)
+	require.Equal(t, types.UID("pod-a-uid"), entry.OwnerReferences[0].UID)
+
+	require.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "ns2", Name: "pod-b.ns2.net1.l2sm"}, entry))
+	require.Empty(t, entry.OwnerReferences)
+
+	// The records registered before the store was enabled are imported.
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1":    {"pod-a.ns1.net1.l2sm"},
+		"2001:db8::1": {"pod-a.ns1.net1.l2sm"},
+		"10.0.0.2":    {"pod-b.ns2.net1.l2sm"},
+		"10.0.0.9":    {"manual.ns1.net1.l2sm"},
+	}, records)
+
+	// The reconciler renders the entries into the Corefile, dropping the records no entry backs.
+	require.NoError(t, backend.AddDNSEntry(ctx, "stray.ns1.net1.l2sm", "10.0.0.8"))
+	reconcile()
+	records, err = backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1":    {"pod-a.ns1.net1.l2sm"},
+		"2001:db8::1": {"pod-a.ns1.net1.l2sm"},
+		"10.0.0.2":    {"pod-b.ns2.net1.l2sm"},
+		"10.0.0.9":    {"manual.ns1.net1.l2sm"},
+	}, records)
+
+	// Entries created with kubectl are rendered too.
+	require.NoError(t, fclient.Create(ctx, &v1alpha1.L2SMDNSEntry{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns3", Name: "gitops"},
+		Spec:       v1alpha1.L2SMDNSEntrySpec{Hostname: "gitops.ns3.net2.l2sm", IPAddresses: []string{"10.0.1.1"}},
+	}))
+	reconcile()
+	records, err = backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"gitops.ns3.net2.l2sm"}, records["10.0.1.1"])
+
+	// Removing an address updates the entry, removing the last one deletes it.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.ns1.net1.l2sm", "2001:db8::1"))
+	require.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod-a.ns1.net1.l2sm"}, entry))
+	require.Equal(t, []string{"10.0.0.1"}, entry.Spec.IPAddresses)
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.ns2.net1.l2sm"))
+	err = fclient.Get(ctx, types.NamespacedName{Namespace: "ns2", Name: "pod-b.ns2.net1.l2sm"}, entry)
+	require.True(t, apierrors.IsNotFound(err))
+
+	reconcile()
+	records, err = backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.ns1.net1.l2sm"},
+		"10.0.0.9": {"manual.ns1.net1.l2sm"},
+		"10.0.1.1": {"gitops.ns3.net2.l2sm"},
+	}, records)
+}
+
+func TestCRDDNSManagerImport(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 pod-a.ns1.net1.l2sm
+    10.0.0.2 pod-b.ns2.net1.l2sm
+    fallthrough
+  }
+}`)
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	cm.Data[configmapmanager.LeasesKey] = fmt.Sprintf(`{"pod-b.ns2.net1.l2sm": %q}`, expiry.Format(time.RFC3339))
+	scheme := createFakeScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	fclient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cm).
+		Build()
+	backend, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Namespace}}.{{.Network}}", "l2sm")
+	require.NoError(t, err)
+	mgr, err := configmapmanager.NewCRDDNSManager(backend, fclient, "test-namespace", names)
+	require.NoError(t, err)
+	reconciler := &controller.EntryReconciler{Client: fclient, DNSManager: backend}
+	ctx := context.Background()
+	before := map[string][]string{
+		"10.0.0.1": {"pod-a.ns1.net1.l2sm"},
+		"10.0.0.2": {"pod-b.ns2.net1.l2sm"},
+	}
+
+	// Before the import, the reconciler renders new entries but keeps the records registered earlier.
+	require.NoError(t, fclient.Create(ctx, &v1alpha1.L2SMDNSEntry{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns3", Name: "pod-c.ns3.net1.l2sm"},
+		Spec:       v1alpha1.L2SMDNSEntrySpec{Hostname: "pod-c.ns3.net1.l2sm", IPAddresses: []string{"10.0.0.3"}},
+	}))
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	records, err := backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	before["10.0.0.3"] = []string{"pod-c.ns3.net1.l2sm"}
+	require.Equal(t, before, records)
+
+	// The import creates an entry for every record, with its lease.
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before, records)
+	entry := &v1alpha1.L2SMDNSEntry{}
+	require.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "ns2", Name: "pod-b.ns2.net1.l2sm"}, entry))
+	require.NotNil(t, entry.Spec.ExpiresAt)
+	require.True(t, expiry.Equal(entry.Spec.ExpiresAt.Time))
+	cfg, err := backend.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.True(t, configmapmanager.EntriesImported(cfg))
+
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	records, err = backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before, records)
+
+	// From then on the entries are the source of truth: a deleted entry is not imported again.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.ns1.net1.l2sm"))
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	records, err = backend.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	delete(before, "10.0.0.1")
+	require.Equal(t, before, records)
+}
+
+func TestCRDDNSManagerLeases(t *testing.T) {
+	scheme := createFakeScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	fclient := crfake.NewClientBuilder().WithScheme(scheme).Build()
+	mgr, err := configmapmanager.NewCRDDNSManager(nil, fclient, "test-namespace", nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Minute, "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+	require.Error(t, mgr.RenewDNSLease(ctx, "pod-c.net1.global.l2sm", time.Minute))
+
+	// Not expired yet.
+	removed, err := mgr.ExpireDNSLeases(ctx, time.Now())
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	removed, err = mgr.ExpireDNSLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, removed)
+
+	entries := &v1alpha1.L2SMDNSEntryList{}
+	require.NoError(t, fclient.List(ctx, entries, client.InNamespace("test-namespace")))
+	require.Len(t, entries.Items, 1)
+	require.Equal(t, "pod-b.net1.global.l2sm", entries.Items[0].Spec.Hostname)
+}
+
+func TestCRDDNSManagerChangeResults(t *testing.T) {
+	scheme := createFakeScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	fclient := crfake.NewClientBuilder().WithScheme(scheme).Build()
+	mgr, err := configmapmanager.NewCRDDNSManager(nil, fclient, "test-namespace", nil)
+	require.NoError(t, err)
+	require.False(t, mgr.AtomicEntryChanges())
+	ctx := context.Background()
+	apply := func(change configmapmanager.EntryChange) configmapmanager.ChangeResult {
+		results, err := mgr.ApplyEntryChanges(ctx, []configmapmanager.EntryChange{change})
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		return results[0]
+	}
+	stored := func() *v1alpha1.L2SMDNSEntry {
+		entry := &v1alpha1.L2SMDNSEntry{}
+		require.NoError(t, fclient.Get(ctx, types.NamespacedName{Namespace: "test-namespace", Name: "pod-a.net1.global.l2sm"}, entry))
+		return entry
+	}
+
+	// Results carry the resourceVersion of the entry, not of the ConfigMap.
+	result := apply(configmapmanager.EntryChange{Op: configmapmanager.EntryAdd, DNSName: "pod-a.net1.global.l2sm", IPAddresses: []string{"fd00::1", "10.0.0.1"}})
+	require.True(t, result.Changed)
+	require.Equal(t, []string{"10.0.0.1", "fd00::1"}, result.IPAddresses)
+	require.Equal(t, stored().ResourceVersion, result.ResourceVersion)
+
+	again := apply(configmapmanager.EntryChange{Op: configmapmanager.EntryAdd, DNSName: "pod-a.net1.global.l2sm", IPAddresses: []string{"10.0.0.1"}})
+	require.False(t, again.Changed)
+	require.Equal(t, result.IPAddresses, again.IPAddresses)
+	require.Equal(t, result.ResourceVersion, again.ResourceVersion)
+
+	result = apply(configmapmanager.EntryChange{Op: configmapmanager.EntryRemove, DNSName: "pod-a.net1.global.l2sm", IPAddresses: []string{"10.0.0.1"}})
+	require.True(t, result.Changed)
+	require.Equal(t, []string{"fd00::1"}, result.IPAddresses)
+	require.Equal(t, stored().ResourceVersion, result.ResourceVersion)
+	require.NotEqual(t, again.ResourceVersion, result.ResourceVersion)
+
+	// A deleted entry has no resourceVersion.
+	result = apply(configmapmanager.EntryChange{Op: configmapmanager.EntryRemove, DNSName: "pod-a.net1.global.l2sm"})
+	require.True(t, result.Changed)
+	require.Empty(t, result.IPAddresses)
+	require.Empty(t, result.ResourceVersion)
+
+	result = apply(configmapmanager.EntryChange{Op: configmapmanager.EntryRemove, DNSName: "pod-a.net1.global.l2sm"})
+	require.False(t, result.Changed)
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/env"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// Environment
+// ----------------------------------------------
+
+func TestValidateEnv(t *testing.T) {
+	require.NoError(t, env.Validate())
+
+	// Malformed values fall back to the default, and Validate names every one of them.
+	t.Setenv("DNS_BATCH_WINDOW", "50")
+	t.Setenv("DNS_RECORD_SHARDS", "four")
+	t.Setenv("ENABLE_DNS_RESPONDER", "yes please")
+	require.Equal(t, time.Duration(0), env.GetBatchWindow())
+	err := env.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid DNS_BATCH_WINDOW "50"`)
+	require.Contains(t, err.Error(), `invalid DNS_RECORD_SHARDS "four"`)
+	require.Contains(t, err.Error(), `invalid ENABLE_DNS_RESPONDER "yes please"`)
+
+	// Fixed values are no longer reported.
+	t.Setenv("DNS_BATCH_WINDOW", "50ms")
+	t.Setenv("DNS_RECORD_SHARDS", "8")
+	t.Setenv("ENABLE_DNS_RESPONDER", "true")
+	require.NoError(t, env.Validate())
+	require.Equal(t, 50*time.Millisecond, env.GetBatchWindow())
+}
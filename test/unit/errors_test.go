@@ -0,0 +1,90 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ----------------------------------------------
+// Error kinds
+// ----------------------------------------------
+
+func TestErrorKinds(t *testing.T) {
+	ctx := context.Background()
+	mgr := newDNSManager(t, createConfigMap("test-cm", "test-namespace", emptyCorefile))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	noHosts := newDNSManager(t, createConfigMap("test-cm", "test-namespace", ".:53 {\n    errors\n}"))
+	missing := newDNSManager(t)
+
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Network}}.{{.Scope}}", "l2sm")
+	require.NoError(t, err)
+	_, missingPod := names.GenerateKey(configmapmanager.DNSEntry{Network: "net1", Scope: "global"})
+	_, invalidNetwork := names.GenerateKey(configmapmanager.DNSEntry{PodName: "pod-a", Network: "Net_1", Scope: "global"})
+	_, addForwardServerErr := mgr.AddForwardServer(ctx, "peer.org:53", configmapmanager.ForwardConfig{Upstreams: []string{"10.0.0.1"}})
+
+	for _, tc := range []struct {
+		name    string
+		err     error
+		kind    error
+		field   string
+		message string
+	}{
+		{"missing field", missingPod, configmapmanager.ErrInvalidArgument, "pod_name", "input entry has fields missing"},
+		{"invalid field", invalidNetwork, configmapmanager.ErrInvalidArgument, "network", "invalid Network"},
+		{"invalid IP", mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "not-an-ip"), configmapmanager.ErrInvalidArgument, "ip_address", "invalid IP address"},
+		{"no IP", mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm"), configmapmanager.ErrInvalidArgument, "ip_address", "at least one IP address is required"},
+		{"invalid lease", mgr.RenewDNSLease(ctx, "pod-a.net1.global.l2sm", 0), configmapmanager.ErrInvalidArgument, "ttl", "lease ttl must be positive"},
+		{"invalid upstream", addForwardServerErr, configmapmanager.ErrInvalidArgument, "upstreams", "invalid upstream"},
+		{"invalid record", mgr.AddRecord(ctx, configmapmanager.Record{Name: "www.example.org", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"}), configmapmanager.ErrInvalidArgument, "name", "is not under the"},
+		{"inter-domain server", mgr.RemoveServerFromConfigMap(ctx, ".:53"), configmapmanager.ErrInvalidArgument, "dom_port", "cannot be removed"},
+		{"unregistered entry", mgr.RenewDNSLease(ctx, "pod-b.net1.global.l2sm", time.Minute), configmapmanager.ErrNotFound, "", "is not registered"},
+		{"missing record", mgr.RemoveRecord(ctx, configmapmanager.Record{Name: "www.net1.global.l2sm", Type: configmapmanager.RecordTypeTXT, Text: []string{"a"}}), configmapmanager.ErrNotFound, "", "not found"},
+		{"missing server", mgr.RemoveServerFromConfigMap(ctx, "peer.org:53"), configmapmanager.ErrNotFound, "", "failed to remove server from corefile"},
+		{"missing ConfigMap", missing.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"), configmapmanager.ErrNotFound, "", "failed to get ConfigMap"},
+		{"CNAME clash", mgr.AddRecord(ctx, configmapmanager.Record{Name: "pod-a.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-b.net1.global.l2sm"}), configmapmanager.ErrAlreadyExists, "name", "a CNAME record cannot share the name"},
+		{"no hosts plugin", noHosts.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"), configmapmanager.ErrFailedPrecondition, "", "could not find 'hosts' plugin"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.err)
+			require.Equal(t, tc.kind, configmapmanager.ErrorKind(tc.err))
+			require.Equal(t, tc.field, configmapmanager.ErrorField(tc.err))
+			require.Contains(t, tc.err.Error(), tc.message)
+		})
+	}
+
+	// The errors of the Kubernetes API are classified too, also when wrapped.
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+	for err, kind := range map[error]error{
+		apierrors.NewConflict(configMaps, "test-cm", errors.New("modified")): configmapmanager.ErrConflict,
+		apierrors.NewAlreadyExists(configMaps, "test-cm"):                    configmapmanager.ErrAlreadyExists,
+		apierrors.NewServiceUnavailable("down"):                              configmapmanager.ErrUnavailable,
+		apierrors.NewTimeoutError("slow", 1):                                 configmapmanager.ErrUnavailable,
+		apierrors.NewForbidden(configMaps, "test-cm", errors.New("denied")):  configmapmanager.ErrFailedPrecondition,
+		context.DeadlineExceeded:                                             configmapmanager.ErrUnavailable,
+		errors.New("unexpected"):                                             nil,
+	} {
+		require.Equal(t, kind, configmapmanager.ErrorKind(fmt.Errorf("failed to update ConfigMap: %w", err)), err.Error())
+	}
+}
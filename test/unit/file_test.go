@@ -0,0 +1,182 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// File storage backend
+// ----------------------------------------------
+func newFileDNSManager(t *testing.T, dir, corefile string) configmapmanager.DNSManager {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Corefile"), []byte(corefile), 0o644))
+	mgr, err := configmapmanager.NewFileDNSManager(dir, "test-cm")
+	require.NoError(t, err)
+	return mgr
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func modTime(t *testing.T, path string) time.Time {
+	t.Helper()
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.ModTime()
+}
+
+func TestFileDNSManager(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+	mgr := newFileDNSManager(t, dir, `.:53 {
+    hosts {
+        fallthrough
+    }
+}`)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+	require.Contains(t, readFile(t, filepath.Join(dir, "Corefile")), "10.0.0.1 pod-a.net1.global.l2sm")
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+	}, records)
+
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+	require.NotContains(t, readFile(t, filepath.Join(dir, "Corefile")), "pod-a")
+
+	// Keys are files of the directory, and removed keys are deleted.
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageHostsFile)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	require.Contains(t, readFile(t, filepath.Join(dir, configmapmanager.HostsKey)), "10.0.0.2 pod-b.net1.global.l2sm")
+	require.NotContains(t, readFile(t, filepath.Join(dir, "Corefile")), "10.0.0.2")
+	// The Corefile that refers to the hosts file is written after it, and the state file last.
+	require.False(t, modTime(t, filepath.Join(dir, "Corefile")).Before(modTime(t, filepath.Join(dir, configmapmanager.HostsKey))))
+	require.False(t, modTime(t, filepath.Join(dir, ".l2smdns-test-cm.json")).Before(modTime(t, filepath.Join(dir, "Corefile"))))
+
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageInline)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	require.NoFileExists(t, filepath.Join(dir, configmapmanager.HostsKey))
+	require.Contains(t, readFile(t, filepath.Join(dir, "Corefile")), "10.0.0.2 pod-b.net1.global.l2sm")
+
+	// Every shard is kept in the same directory.
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageSharded)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	require.Contains(t, readFile(t, filepath.Join(dir, "db.net1.global.l2sm")), "pod-b.net1.global.l2sm.")
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.2": {"pod-b.net1.global.l2sm"}}, records)
+
+	// A missing directory is refused.
+	_, err = configmapmanager.NewFileDNSManager(filepath.Join(dir, "missing"), "test-cm")
+	require.Error(t, err)
+}
+
+func TestFileDNSManagerConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+	first := newFileDNSManager(t, dir, `.:53 {
+    hosts {
+        fallthrough
+    }
+}`)
+	second, err := configmapmanager.NewFileDNSManager(dir, "test-cm")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i, mgr := range []configmapmanager.DNSManager{first, second} {
+		for j := 0; j < 10; j++ {
+			wg.Add(1)
+			go func(i, j int, mgr configmapmanager.DNSManager) {
+				defer wg.Done()
+				name := fmt.Sprintf("pod-%d-%d.net1.global.l2sm", i, j)
+				require.NoError(t, mgr.AddDNSEntry(ctx, name, fmt.Sprintf("10.0.%d.%d", i, j+1)))
+			}(i, j, mgr)
+		}
+	}
+	wg.Wait()
+
+	records, err := first.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 20)
+}
+
+func TestFileDNSManagerWatch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+	watcher := newFileDNSManager(t, dir, `.:53 {
+    hosts {
+        10.0.0.1 pod-a.net1.global.l2sm
+        fallthrough
+    }
+}`)
+	writer, err := configmapmanager.NewFileDNSManager(dir, "test-cm")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	records, _, events, err := watcher.WatchDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, records)
+
+	require.NoError(t, writer.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+	event := nextEvent(t, events)
+	require.Equal(t, configmapmanager.RecordAdded, event.Type)
+	require.Equal(t, "10.0.0.2", event.IPAddress)
+	require.Equal(t, "pod-b.net1.global.l2sm", event.DNSName)
+}
+
+func TestFileBackendEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	interDomain := fmt.Sprintf(".:%d", freePort(t))
+	t.Setenv("INTER_DOMAIN_DOM_PORT", interDomain)
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+
+	mgr := newFileDNSManager(t, dir, fmt.Sprintf(`%s {
+  bind 127.0.0.1
+  hosts {
+  }
+}`, interDomain))
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.1.1"))
+
+	// CoreDNS serves the Corefile written to the directory.
+	addr := startCoreDNS(t, dir, map[string]string{"Corefile": readFile(t, filepath.Join(dir, "Corefile"))})
+	resp := query(t, addr, "pod-a.net1.global.l2sm", dns.TypeA)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "10.0.1.1", resp.Answer[0].(*dns.A).A.String())
+}
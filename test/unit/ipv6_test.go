@@ -0,0 +1,121 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// NormalizeIP
+// ----------------------------------------------
+func TestNormalizeIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		ipAddress string
+		expected  string
+		expectErr bool
+	}{
+		{name: "IPv4", ipAddress: "10.0.0.1", expected: "10.0.0.1"},
+		{name: "Compressed IPv6", ipAddress: "2001:db8::1", expected: "2001:db8::1"},
+		{name: "Expanded IPv6", ipAddress: "2001:0db8:0000:0000:0000:0000:0000:0001", expected: "2001:db8::1"},
+		{name: "Partially compressed IPv6", ipAddress: "2001:db8:0:0::1", expected: "2001:db8::1"},
+		{name: "Upper-case IPv6", ipAddress: "FD00::A", expected: "fd00::a"},
+		{name: "IPv4-mapped IPv6", ipAddress: "::ffff:10.0.0.1", expected: "10.0.0.1"},
+		{name: "Not an IP", ipAddress: "NOT_AN_IP", expectErr: true},
+		{name: "IPv6 with zone", ipAddress: "fe80::1%eth0", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ip, err := configmapmanager.NormalizeIP(tc.ipAddress)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid IP address")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ip)
+		})
+	}
+}
+
+// ----------------------------------------------
+// Dual-stack entries
+// ----------------------------------------------
+func TestDualStackDNSEntry(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    2001:0db8:0000:0000:0000:0000:0000:0002 other.net1.global.l2sm
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	// The same IPv6 address in two notations must only be stored once.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "2001:db8:0:0::1", "2001:0db8::0001"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "2001:DB8::1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "2001:db8::2"))
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1":    {"pod-a.net1.global.l2sm"},
+		"2001:db8::1": {"pod-a.net1.global.l2sm"},
+		"2001:db8::2": {"other.net1.global.l2sm", "pod-b.net1.global.l2sm"},
+	}, records)
+
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Contains(t, cfg.Data["Corefile"], "        10.0.0.1 pod-a.net1.global.l2sm\n        2001:db8::1 pod-a.net1.global.l2sm\n")
+	require.NotContains(t, cfg.Data["Corefile"], "0db8")
+
+	// Removing through an expanded notation removes the compressed record.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net1.global.l2sm", "2001:0db8:0:0:0:0:0:2"))
+	// Removing without addresses removes both the IPv4 and the IPv6 record.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"2001:db8::2": {"other.net1.global.l2sm"},
+	}, records)
+}
+
+func TestDualStackDNSEntryInvalidAddress(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	err := mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "2001:db8::zz")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid IP address")
+
+	err = mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one IP address is required")
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	dnsapi "github.com/Networks-it-uc3m/l2sm-dns/api/v1/dns"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/l2smdns"
+	"github.com/coredns/caddy"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/pkg/fall"
+	"github.com/coredns/coredns/plugin/test"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// ----------------------------------------------
+// CoreDNS plugin
+// ----------------------------------------------
+
+// fakeDNSService streams a snapshot of entries, then the events sent to it, and lists records.
+type fakeDNSService struct {
+	dnsapi.UnimplementedDnsServiceServer
+	entries []*dnsapi.DNSEntry
+	records []*dnsapi.Record
+	events  chan *dnsapi.WatchEntriesResponse
+}
+
+func (s *fakeDNSService) WatchEntries(req *dnsapi.WatchEntriesRequest, stream dnsapi.DnsService_WatchEntriesServer) error {
+	for _, entry := range s.entries {
+		if err := stream.Send(&dnsapi.WatchEntriesResponse{Type: dnsapi.EventType_EVENT_TYPE_ADDED, Entry: entry, ResourceVersion: "1", Snapshot: true}); err != nil {
+			return err
+		}
+	}
+	if err := stream.Send(&dnsapi.WatchEntriesResponse{Type: dnsapi.EventType_EVENT_TYPE_SYNCED, ResourceVersion: "1", Snapshot: true}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case event := <-s.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *fakeDNSService) ListRecords(ctx context.Context, req *dnsapi.ListRecordsRequest) (*dnsapi.ListRecordsResponse, error) {
+	return &dnsapi.ListRecordsResponse{Records: s.records}, nil
+}
+
+func startFakeDNSService(t *testing.T, service *fakeDNSService) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	dnsapi.RegisterDnsServiceServer(server, service)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func serve(t *testing.T, l *l2smdns.L2SMDNS, tc test.Case) *dns.Msg {
+	t.Helper()
+	rec := dnstest.NewRecorder(&test.ResponseWriter{})
+	_, err := l.ServeDNS(context.Background(), rec, tc.Msg())
+	require.NoError(t, err)
+	return rec.Msg
+}
+
+// rcode returns the rcode of the reply to qname, or the one returned by ServeDNS if it wrote none,
+// as test.NextHandler does.
+func rcode(t *testing.T, l *l2smdns.L2SMDNS, qname string) int {
+	t.Helper()
+	rec := dnstest.NewRecorder(&test.ResponseWriter{})
+	code, err := l.ServeDNS(context.Background(), rec, test.Case{Qname: qname, Qtype: dns.TypeA}.Msg())
+	require.NoError(t, err)
+	if rec.Msg != nil {
+		return rec.Msg.Rcode
+	}
+	return code
+}
+
+func TestL2SMDNSPluginParse(t *testing.T) {
+	l, err := l2smdns.Parse(caddy.NewTestController("dns", `l2smdns 10.0.0.1:9090 {
+    tld example
+    template {{.PodName}}.{{.Network}}
+    refresh 5s
+    fallthrough
+}`))
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1:9090", l.Addr)
+	require.Equal(t, "example", l.Names.TLD())
+	require.Equal(t, 5*time.Second, l.RefreshInterval)
+	require.True(t, l.Fall.Through("pod-a.net1.example."))
+
+	l, err = l2smdns.Parse(caddy.NewTestController("dns", `l2smdns`))
+	require.NoError(t, err)
+	require.Equal(t, "localhost:8081", l.Addr)
+	require.Equal(t, "l2sm", l.Names.TLD())
+	require.False(t, l.Fall.Through("pod-a.net1.global.l2sm."))
+
+	for _, input := range []string{
+		`l2smdns a:1 b:2`,
+		`l2smdns { refresh never }`,
+		`l2smdns { template {{.Unknown}} }`,
+		`l2smdns { unknown }`,
+	} {
+		_, err := l2smdns.Parse(caddy.NewTestController("dns", input))
+		require.Error(t, err, input)
+	}
+}
+
+func TestL2SMDNSPlugin(t *testing.T) {
+	service := &fakeDNSService{
+		entries: []*dnsapi.DNSEntry{
+			{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.1", "fd00::1"}},
+		},
+		records: []*dnsapi.Record{
+			{Name: "www.net1.global.l2sm", Type: dnsapi.RecordType_RECORD_TYPE_CNAME, Ttl: 60, Target: "pod-a.net1.global.l2sm"},
+		},
+		events: make(chan *dnsapi.WatchEntriesResponse),
+	}
+	addr := startFakeDNSService(t, service)
+
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Network}}.{{.Scope}}", "l2sm")
+	require.NoError(t, err)
+	l := l2smdns.New(addr, names)
+	l.Next = test.NextHandler(dns.RcodeRefused, nil)
+	require.NoError(t, l.Start())
+	defer l.Stop()
+	require.Eventually(t, l.Ready, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return len(serve(t, l, test.Case{Qname: "www.net1.global.l2sm.", Qtype: dns.TypeCNAME}).Answer) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, tc := range []test.Case{
+		{
+			Qname: "pod-a.net1.global.l2sm.", Qtype: dns.TypeA,
+			Answer: []dns.RR{test.A("pod-a.net1.global.l2sm. 3600 IN A 10.0.0.1")},
+		},
+		{
+			Qname: "pod-a.net1.global.l2sm.", Qtype: dns.TypeAAAA,
+			Answer: []dns.RR{test.AAAA("pod-a.net1.global.l2sm. 3600 IN AAAA fd00::1")},
+		},
+		{
+			Qname: "1.0.0.10.in-addr.arpa.", Qtype: dns.TypePTR,
+			Answer: []dns.RR{test.PTR("1.0.0.10.in-addr.arpa. 3600 IN PTR pod-a.net1.global.l2sm.")},
+		},
+		{
+			Qname: "www.net1.global.l2sm.", Qtype: dns.TypeA,
+			// SortAndCheck sorts the answer, which puts the A record first.
+			Answer: []dns.RR{
+				test.A("pod-a.net1.global.l2sm. 3600 IN A 10.0.0.1"),
+				test.CNAME("www.net1.global.l2sm. 60 IN CNAME pod-a.net1.global.l2sm."),
+			},
+		},
+	} {
+		require.NoError(t, test.SortAndCheck(serve(t, l, tc), tc), tc.Qname)
+	}
+
+	// Names outside the zone go to the next plugin, and missing names of the zone do not.
+	require.Equal(t, dns.RcodeRefused, rcode(t, l, "example.org."))
+	require.Equal(t, dns.RcodeNameError, rcode(t, l, "pod-b.net1.global.l2sm."))
+	l.Fall = fall.Root
+	require.Equal(t, dns.RcodeRefused, rcode(t, l, "pod-b.net1.global.l2sm."))
+	l.Fall = fall.Zero
+
+	// Streamed changes resolve at once.
+	service.events <- &dnsapi.WatchEntriesResponse{
+		Type:            dnsapi.EventType_EVENT_TYPE_ADDED,
+		Entry:           &dnsapi.DNSEntry{PodName: "pod-b", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.2"}},
+		ResourceVersion: "2",
+	}
+	service.events <- &dnsapi.WatchEntriesResponse{
+		Type:            dnsapi.EventType_EVENT_TYPE_DELETED,
+		Entry:           &dnsapi.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global", IpAddresses: []string{"10.0.0.1", "fd00::1"}},
+		ResourceVersion: "3",
+	}
+	require.Eventually(t, func() bool {
+		return rcode(t, l, "pod-a.net1.global.l2sm.") == dns.RcodeNameError
+	}, 5*time.Second, 10*time.Millisecond)
+	tc := test.Case{
+		Qname: "pod-b.net1.global.l2sm.", Qtype: dns.TypeA,
+		Answer: []dns.RR{test.A("pod-b.net1.global.l2sm. 3600 IN A 10.0.0.2")},
+	}
+	require.NoError(t, test.SortAndCheck(serve(t, l, tc), tc))
+}
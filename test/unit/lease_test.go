@@ -0,0 +1,193 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func leasesOf(t *testing.T, mgr configmapmanager.DNSManager) map[string]time.Time {
+	cfg, err := mgr.GetConfigMap(context.Background())
+	require.NoError(t, err)
+	leases := map[string]time.Time{}
+	if data, ok := cfg.Data[configmapmanager.LeasesKey]; ok {
+		require.NoError(t, json.Unmarshal([]byte(data), &leases))
+	}
+	return leases
+}
+
+// ----------------------------------------------
+// Leases
+// ----------------------------------------------
+func TestDNSLeases(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    fallthrough
+  }
+}`)
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "leased.net1.global.l2sm", time.Minute, "10.0.0.1", "2001:db8::1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "static.net1.global.l2sm", "10.0.0.2"))
+
+	leases := leasesOf(t, mgr)
+	require.Len(t, leases, 1)
+	expiry := leases["leased.net1.global.l2sm"]
+	require.WithinDuration(t, time.Now().Add(time.Minute), expiry, 2*time.Second)
+
+	t.Run("nothing expires early", func(t *testing.T) {
+		removed, err := mgr.ExpireDNSLeases(ctx, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, removed)
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		require.NoError(t, mgr.RenewDNSLease(ctx, "leased.net1.global.l2sm", time.Hour))
+		require.WithinDuration(t, time.Now().Add(time.Hour), leasesOf(t, mgr)["leased.net1.global.l2sm"], 2*time.Second)
+
+		err := mgr.RenewDNSLease(ctx, "unknown.net1.global.l2sm", time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not registered")
+
+		err = mgr.RenewDNSLease(ctx, "leased.net1.global.l2sm", 0)
+		require.Error(t, err)
+	})
+
+	t.Run("expire after restart", func(t *testing.T) {
+		// A new manager reads the leases back from the ConfigMap.
+		restarted, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+		require.NoError(t, err)
+
+		removed, err := restarted.ExpireDNSLeases(ctx, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{
+			"10.0.0.1":    {"leased.net1.global.l2sm"},
+			"2001:db8::1": {"leased.net1.global.l2sm"},
+		}, removed)
+
+		records, err := restarted.ListDNSRecords(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{"10.0.0.2": {"static.net1.global.l2sm"}}, records)
+		require.Empty(t, leasesOf(t, restarted))
+
+		cfg, err := restarted.GetConfigMap(ctx)
+		require.NoError(t, err)
+		require.NotContains(t, cfg.Data, configmapmanager.LeasesKey)
+	})
+}
+
+func TestDNSLeaseLifecycle(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	t.Run("deleting the entry drops its lease", func(t *testing.T) {
+		require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Minute, "10.0.0.1"))
+		require.Len(t, leasesOf(t, mgr), 1)
+
+		require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+		require.Empty(t, leasesOf(t, mgr))
+	})
+
+	t.Run("adding without a ttl makes the entry permanent", func(t *testing.T) {
+		require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-b.net1.global.l2sm", time.Minute, "10.0.0.2"))
+		require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-b.net1.global.l2sm", 0, "10.0.0.2"))
+		require.Empty(t, leasesOf(t, mgr))
+
+		removed, err := mgr.ExpireDNSLeases(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, removed)
+	})
+
+	t.Run("expiring without lapsed leases skips the update", func(t *testing.T) {
+		require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-c.net1.global.l2sm", time.Hour, "10.0.0.3"))
+		before, err := mgr.GetConfigMap(ctx)
+		require.NoError(t, err)
+
+		removed, err := mgr.ExpireDNSLeases(ctx, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, removed)
+
+		after, err := mgr.GetConfigMap(ctx)
+		require.NoError(t, err)
+		require.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	})
+}
+
+func TestExpireDNSLeasesReadsOnlyTheLeases(t *testing.T) {
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageSharded)
+	t.Setenv("DNS_RECORD_SHARDS", "4")
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	var gets, updates int32
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				atomic.AddInt32(&gets, 1)
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				atomic.AddInt32(&updates, 1)
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Without leases, or with none due, only the main ConfigMap is read, and nothing is written.
+	expire := func(now time.Time) map[string][]string {
+		atomic.StoreInt32(&gets, 0)
+		atomic.StoreInt32(&updates, 0)
+		removed, err := mgr.ExpireDNSLeases(ctx, now)
+		require.NoError(t, err)
+		return removed
+	}
+	require.Empty(t, expire(time.Now()))
+	require.Equal(t, int32(1), atomic.LoadInt32(&gets))
+	require.Zero(t, atomic.LoadInt32(&updates))
+
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-a.net1.global.l2sm", time.Hour, "10.0.0.1"))
+	require.Empty(t, expire(time.Now()))
+	require.Equal(t, int32(1), atomic.LoadInt32(&gets))
+	require.Zero(t, atomic.LoadInt32(&updates))
+
+	require.Equal(t, map[string][]string{"10.0.0.1": {"pod-a.net1.global.l2sm"}}, expire(time.Now().Add(2*time.Hour)))
+	require.NotZero(t, atomic.LoadInt32(&updates))
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// NameTemplate
+// ----------------------------------------------
+func TestNewNameTemplateEdgeCases(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		tld            string
+		expectedErrMsg string
+	}{
+		{name: "Unparseable template", text: "{{.PodName", tld: "l2sm", expectedErrMsg: "invalid name template"},
+		{name: "Unknown field", text: "{{.Pod}}.{{.Network}}", tld: "l2sm", expectedErrMsg: "invalid name template"},
+		{name: "No field referenced", text: "static", tld: "l2sm", expectedErrMsg: "must reference at least one field"},
+		{name: "Invalid TLD", text: "{{.PodName}}", tld: "L2SM_", expectedErrMsg: "invalid TLD"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := configmapmanager.NewNameTemplate(tc.text, tc.tld)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErrMsg)
+		})
+	}
+}
+
+func TestNameTemplateGenerateKeyEdgeCases(t *testing.T) {
+	nt, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Namespace}}.{{.Network}}.{{.Cluster}}", "l2sm.example.org.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"PodName", "Namespace", "Network", "Cluster"}, nt.Fields())
+	require.Equal(t, "l2sm.example.org", nt.TLD())
+
+	valid := configmapmanager.DNSEntry{PodName: "pod-a", Namespace: "default", Network: "net1", Cluster: "edge-1"}
+
+	tests := []struct {
+		name           string
+		entry          func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry
+		expected       string
+		expectedErrMsg string
+	}{
+		{
+			name:     "Valid entry, Scope is not part of the template",
+			entry:    func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry { return e },
+			expected: "pod-a.default.net1.edge-1.l2sm.example.org",
+		},
+		{
+			name:           "Missing field",
+			entry:          func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry { e.Cluster = ""; return e },
+			expectedErrMsg: "input entry has fields missing. Fields [Cluster] must be filled",
+		},
+		{
+			name: "Label too long",
+			entry: func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry {
+				e.PodName = strings.Repeat("a", 64)
+				return e
+			},
+			expectedErrMsg: "must be no more than 63 characters",
+		},
+		{
+			name:           "Invalid characters",
+			entry:          func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry { e.Network = "net_1"; return e },
+			expectedErrMsg: "invalid Network \"net_1\"",
+		},
+		{
+			name:           "Upper-case characters",
+			entry:          func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry { e.PodName = "Pod-A"; return e },
+			expectedErrMsg: "invalid PodName \"Pod-A\"",
+		},
+		{
+			name:           "Label starting with a hyphen",
+			entry:          func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry { e.Namespace = "-default"; return e },
+			expectedErrMsg: "invalid Namespace",
+		},
+		{
+			name: "Name too long",
+			entry: func(e configmapmanager.DNSEntry) configmapmanager.DNSEntry {
+				e.PodName, e.Namespace, e.Network, e.Cluster = strings.Repeat("a", 63), strings.Repeat("b", 63), strings.Repeat("c", 63), strings.Repeat("d", 63)
+				return e
+			},
+			expectedErrMsg: "must be no more than 253",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := nt.GenerateKey(tc.entry(valid))
+			if tc.expectedErrMsg != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, key)
+
+			parsed, err := nt.ParseKey(key)
+			require.NoError(t, err)
+			require.Equal(t, tc.entry(valid), parsed)
+		})
+	}
+}
+
+func TestNameTemplateParseKeyEdgeCases(t *testing.T) {
+	nt, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Network}}.{{.Scope}}", "cluster.l2sm")
+	require.NoError(t, err)
+
+	_, err = nt.ParseKey("pod-a.net1.global.l2sm")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not an L2SM name")
+
+	_, err = nt.ParseKey("pod-a.global.cluster.l2sm")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not match the name template")
+
+	entry, err := nt.ParseKey("pod-a.net1.global.cluster.l2sm.")
+	require.NoError(t, err)
+	require.Equal(t, configmapmanager.DNSEntry{PodName: "pod-a", Network: "net1", Scope: "global"}, entry)
+}
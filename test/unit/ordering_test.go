@@ -0,0 +1,90 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/corefile"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// Hosts ordering
+// ----------------------------------------------
+func TestReplaceHostsEntriesIsDeterministic(t *testing.T) {
+	entries := map[string][]string{
+		"10.0.0.10":   {"c.net1.global.l2sm"},
+		"2001:db8::1": {"d.net1.global.l2sm"},
+		"10.0.0.2":    {"b.net1.global.l2sm", "a.net1.global.l2sm"},
+		"10.0.0.1":    {"e.net1.global.l2sm"},
+	}
+	expected := `.:53 {
+    hosts {
+        10.0.0.1 e.net1.global.l2sm
+        10.0.0.2 b.net1.global.l2sm a.net1.global.l2sm
+        10.0.0.10 c.net1.global.l2sm
+        2001:db8::1 d.net1.global.l2sm
+        fallthrough
+    }
+}
+`
+
+	for i := 0; i < 10; i++ {
+		cf, err := corefile.New(`.:53 {
+  hosts {
+    fallthrough
+  }
+}`)
+		require.NoError(t, err)
+		server, ok := cf.GetServer(".:53")
+		require.True(t, ok)
+		hostsPlugin, ok := server.GetPlugin("hosts")
+		require.True(t, ok)
+
+		require.NoError(t, hostsPlugin.ReplaceHostsEntries(entries))
+		require.Equal(t, expected, cf.ToString())
+
+		listed, err := hostsPlugin.ListHostsEntries()
+		require.NoError(t, err)
+		require.Equal(t, entries, listed)
+	}
+}
+
+// ----------------------------------------------
+// No-op mutations
+// ----------------------------------------------
+func TestNoOpMutationSkipsUpdate(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+
+	// Re-adding the same entry and removing an unknown one must not write the ConfigMap again.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+
+	unchanged, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, cfg.ResourceVersion, unchanged.ResourceVersion)
+	require.Equal(t, cfg.Data["Corefile"], unchanged.Data["Corefile"])
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/controller"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// ----------------------------------------------
+// Pod controller
+// ----------------------------------------------
+
+// podNames returns a naming template that tells pods of different namespaces apart, as the pod
+// controller requires.
+func podNames(t *testing.T) *configmapmanager.NameTemplate {
+	t.Helper()
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Namespace}}.{{.Network}}.{{.Scope}}", "l2sm")
+	require.NoError(t, err)
+	return names
+}
+
+func TestPodReconciler(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.9 other.net1.global.l2sm
+    fallthrough
+  }
+}`)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-a",
+			Namespace: "default",
+			Annotations: map[string]string{
+				controller.L2SMNetworksAnnotation:  `[{"name": "net1", "ips": ["10.0.0.1/24"]}, {"name": "net2"}]`,
+				controller.NetworkStatusAnnotation: `[{"name": "default/net2", "ips": ["10.0.1.1", "2001:db8::1"]}]`,
+			},
+		},
+	}
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm, pod).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+
+	reconciler := &controller.PodReconciler{Client: fclient, DNSManager: mgr, Scope: "global", Names: podNames(t)}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod-a"}}
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.9":    {"other.net1.global.l2sm"},
+		"10.0.0.1":    {"pod-a.default.net1.global.l2sm"},
+		"10.0.1.1":    {"pod-a.default.net2.global.l2sm"},
+		"2001:db8::1": {"pod-a.default.net2.global.l2sm"},
+	}, records)
+
+	// Reconciling again is a no-op.
+	before, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	after, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before.ResourceVersion, after.ResourceVersion)
+
+	// The pod loses its attachment to net2.
+	require.NoError(t, fclient.Get(ctx, req.NamespacedName, pod))
+	pod.Annotations[controller.L2SMNetworksAnnotation] = `[{"name": "net1", "ips": ["10.0.0.1"]}]`
+	require.NoError(t, fclient.Update(ctx, pod))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.9": {"other.net1.global.l2sm"},
+		"10.0.0.1": {"pod-a.default.net1.global.l2sm"},
+	}, records)
+
+	// The pod is deleted.
+	require.NoError(t, fclient.Delete(ctx, pod))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.9": {"other.net1.global.l2sm"},
+	}, records)
+}
+
+func TestPodReconcilerCommaSeparatedNetworks(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-b",
+			Namespace: "default",
+			Annotations: map[string]string{
+				controller.L2SMNetworksAnnotation:  "net1, net3",
+				controller.NetworkStatusAnnotation: `[{"name": "cbr0", "ips": ["10.244.0.5"], "default": true}, {"name": "net1", "ips": ["10.0.0.2"]}]`,
+			},
+		},
+	}
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm, pod).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+
+	reconciler := &controller.PodReconciler{Client: fclient, DNSManager: mgr, Scope: "global", Names: podNames(t)}
+	ctx := context.Background()
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "pod-b"}})
+	require.NoError(t, err)
+
+	// net3 has no address yet, and the default network is never registered.
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.2": {"pod-b.default.net1.global.l2sm"},
+	}, records)
+}
+
+func TestPodReconcilerNamespaces(t *testing.T) {
+	// web.ns-c was registered through the API, for a pod the controller does not know.
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.3 web.ns-c.net1.global.l2sm
+  }
+}`)
+	pods := []*corev1.Pod{}
+	for i, namespace := range []string{"ns-a", "ns-b"} {
+		pods = append(pods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "web",
+				Namespace:   namespace,
+				Annotations: map[string]string{controller.L2SMNetworksAnnotation: fmt.Sprintf(`[{"name": "net1", "ips": ["10.0.0.%d"]}]`, i+1)},
+			},
+		})
+	}
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm, pods[0], pods[1]).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+
+	reconciler := &controller.PodReconciler{Client: fclient, DNSManager: mgr, Scope: "global", Names: podNames(t)}
+	ctx := context.Background()
+	for _, pod := range pods {
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+		require.NoError(t, err)
+	}
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"web.ns-a.net1.global.l2sm"},
+		"10.0.0.2": {"web.ns-b.net1.global.l2sm"},
+		"10.0.0.3": {"web.ns-c.net1.global.l2sm"},
+	}, records)
+
+	// Deleting ns-a/web leaves the entries of the other pods named web alone.
+	require.NoError(t, fclient.Delete(ctx, pods[0]))
+	_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pods[0])})
+	require.NoError(t, err)
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.2": {"web.ns-b.net1.global.l2sm"},
+		"10.0.0.3": {"web.ns-c.net1.global.l2sm"},
+	}, records)
+}
+
+func TestPodReconcilerNeedsNamespaceInNames(t *testing.T) {
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Network}}.{{.Scope}}", "l2sm")
+	require.NoError(t, err)
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.2 web.net1.global.l2sm
+  }
+}`)
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+
+	reconciler := &controller.PodReconciler{Client: fclient, DNSManager: mgr, Scope: "global", Names: names}
+	require.ErrorContains(t, reconciler.SetupWithManager(nil), "{{.Namespace}}")
+	_, err = reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns-a", Name: "web"}})
+	require.Error(t, err)
+
+	records, err := mgr.ListDNSRecords(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.2": {"web.net1.global.l2sm"}}, records)
+}
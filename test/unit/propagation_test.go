@@ -0,0 +1,73 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/propagation"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// Propagation
+// ----------------------------------------------
+
+func TestWaitForAddresses(t *testing.T) {
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	waiter := &propagation.Waiter{Endpoint: startResponder(t, mgr), Interval: 10 * time.Millisecond}
+
+	// The wait returns once the entry is written and served.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = mgr.AddDNSEntry(context.Background(), "pod-a.net1.global.l2sm", "10.0.0.1", "fd00::1")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, waiter.WaitForAddresses(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "fd00::1"))
+
+	// Other addresses of the name do not resolve the wait, nor do missing names.
+	for _, addresses := range [][]string{{"10.0.0.2"}, {"10.0.0.1", "10.0.0.2"}} {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := waiter.WaitForAddresses(ctx, "pod-a.net1.global.l2sm", addresses...)
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, waiter.WaitForAddresses(ctx, "pod-b.net1.global.l2sm", "10.0.0.1"), context.DeadlineExceeded)
+
+	// Invalid addresses fail at once.
+	require.Error(t, waiter.WaitForAddresses(context.Background(), "pod-a.net1.global.l2sm", "not-an-ip"))
+	require.Error(t, waiter.WaitForAddresses(context.Background(), "pod-a.net1.global.l2sm"))
+}
+
+func TestWaitForAddressesDefaultInterval(t *testing.T) {
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	require.NoError(t, mgr.AddDNSEntry(context.Background(), "pod-a.net1.global.l2sm", "10.0.0.1"))
+
+	// A waiter without an interval queries at the default one.
+	for _, interval := range []time.Duration{0, -time.Second} {
+		waiter := &propagation.Waiter{Endpoint: startResponder(t, mgr), Interval: interval}
+		require.NoError(t, waiter.WaitForAddresses(context.Background(), "pod-a.net1.global.l2sm", "10.0.0.1"))
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		err := waiter.WaitForAddresses(ctx, "pod-b.net1.global.l2sm", "10.0.0.2")
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+}
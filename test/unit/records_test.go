@@ -0,0 +1,185 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// CNAME / SRV / TXT records
+// ----------------------------------------------
+func TestRecords(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 pod-a.net1.global.l2sm
+  }
+  forward . /etc/resolv.conf
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	cname := configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"}
+	srv := configmapmanager.Record{Name: "_http._tcp.web.net1.global.l2sm", Type: configmapmanager.RecordTypeSRV, TTL: 60, Priority: 10, Weight: 5, Port: 8080, Target: "pod-a.net1.global.l2sm"}
+	txt := configmapmanager.Record{Name: "pod-a.net1.global.l2sm", Type: configmapmanager.RecordTypeTXT, Text: []string{"owner=team-a", "tier=frontend"}}
+	for _, record := range []configmapmanager.Record{cname, srv, txt, cname} {
+		require.NoError(t, mgr.AddRecord(ctx, record))
+	}
+
+	records, err := mgr.ListRecords(ctx)
+	require.NoError(t, err)
+	cname.TTL, txt.TTL = 3600, 3600
+	require.Equal(t, []configmapmanager.Record{srv, txt, cname}, records)
+
+	// The zone is served by a file plugin, and the hosts plugin falls through to it.
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Contains(t, cfg.Data["Corefile"], `    hosts {
+        10.0.0.1 pod-a.net1.global.l2sm
+        fallthrough l2sm
+    }
+    forward . /etc/resolv.conf
+    file /etc/coredns/l2sm.db l2sm
+`)
+
+	// The zone file is valid and its serial increases with every change.
+	parser := dns.NewZoneParser(strings.NewReader(cfg.Data[configmapmanager.ZoneKey]), "l2sm.", "")
+	rr, ok := parser.Next()
+	require.True(t, ok)
+	soa, ok := rr.(*dns.SOA)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), soa.Serial)
+	count := 1
+	for _, ok := parser.Next(); ok; _, ok = parser.Next() {
+		count++
+	}
+	require.NoError(t, parser.Err())
+	require.Equal(t, 4, count)
+
+	// Removing every record removes the file plugin and the zone, but keeps the fallthrough.
+	for _, record := range []configmapmanager.Record{cname, srv, txt} {
+		record.TTL = 0
+		require.NoError(t, mgr.RemoveRecord(ctx, record))
+	}
+	records, err = mgr.ListRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data, configmapmanager.ZoneKey)
+	require.NotContains(t, cfg.Data["Corefile"], "file ")
+	require.Contains(t, cfg.Data["Corefile"], "fallthrough l2sm")
+}
+
+func TestRecordsEdgeCases(t *testing.T) {
+	validCorefile := `.:53 {
+  hosts {
+    10.0.0.1 pod-a.net1.global.l2sm
+  }
+}`
+
+	tests := []struct {
+		name           string
+		corefileData   string
+		existing       []configmapmanager.Record
+		record         configmapmanager.Record
+		remove         bool
+		expectedErrMsg string
+	}{
+		{
+			name:           "No 'Corefile' key in ConfigMap",
+			corefileData:   "",
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"},
+			expectedErrMsg: "corefile not found in ConfigMap data",
+		},
+		{
+			name:           "Name outside the TLD",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "web.example.org", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"},
+			expectedErrMsg: "is not under the \"l2sm\" zone",
+		},
+		{
+			name:           "Zone apex",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "l2sm", Type: configmapmanager.RecordTypeTXT, Text: []string{"x"}},
+			expectedErrMsg: "is not under the \"l2sm\" zone",
+		},
+		{
+			name:           "Unsupported type",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: "MX", Target: "pod-a.net1.global.l2sm"},
+			expectedErrMsg: "unsupported record type",
+		},
+		{
+			name:           "CNAME without target",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME},
+			expectedErrMsg: "invalid CNAME target",
+		},
+		{
+			name:           "TXT without text",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeTXT},
+			expectedErrMsg: "has no text",
+		},
+		{
+			name:           "CNAME on a name with addresses",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "pod-a.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-b.net1.global.l2sm"},
+			expectedErrMsg: "cannot share the name",
+		},
+		{
+			name:           "CNAME on a name with other records",
+			corefileData:   validCorefile,
+			existing:       []configmapmanager.Record{{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeTXT, Text: []string{"x"}}},
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"},
+			expectedErrMsg: "cannot share the name",
+		},
+		{
+			name:           "Remove a missing record",
+			corefileData:   validCorefile,
+			record:         configmapmanager.Record{Name: "web.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, Target: "pod-a.net1.global.l2sm"},
+			remove:         true,
+			expectedErrMsg: "CNAME record \"web.net1.global.l2sm\" not found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := createConfigMap("test-cm", "test-namespace", tc.corefileData)
+			mgr := newDNSManager(t, cm)
+			ctx := context.Background()
+			for _, record := range tc.existing {
+				require.NoError(t, mgr.AddRecord(ctx, record))
+			}
+
+			var err error
+			if tc.remove {
+				err = mgr.RemoveRecord(ctx, tc.record)
+			} else {
+				err = mgr.AddRecord(ctx, tc.record)
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErrMsg)
+		})
+	}
+}
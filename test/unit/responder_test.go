@@ -0,0 +1,145 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/responder"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// DNS responder
+// ----------------------------------------------
+
+// startResponder serves the records of mgr with a DNS responder, and returns its address.
+func startResponder(t *testing.T, mgr configmapmanager.DNSManager, upstreams ...string) string {
+	addr := fmt.Sprintf("127.0.0.1:%d", freePort(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- (&responder.Responder{
+			DNSManager:      mgr,
+			Addr:            addr,
+			TLD:             "l2sm",
+			Upstreams:       upstreams,
+			RefreshInterval: 20 * time.Millisecond,
+		}).Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		_, _, err := (&dns.Client{Timeout: 100 * time.Millisecond}).Exchange(new(dns.Msg).SetQuestion("l2sm.", dns.TypeSOA), addr)
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+	return addr
+}
+
+// startUpstream serves every query with an A record of ip, and returns its address.
+func startUpstream(t *testing.T, ip string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		reply := new(dns.Msg)
+		reply.SetReply(req)
+		reply.Answer = append(reply.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+			A:   net.ParseIP(ip),
+		})
+		_ = w.WriteMsg(reply)
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+// eventuallyAnswers waits until the answer to name has the given records, in presentation format.
+func eventuallyAnswers(t *testing.T, addr, name string, qtype uint16, expected ...string) {
+	t.Helper()
+	var last []string
+	require.Eventually(t, func() bool {
+		last = nil
+		for _, rr := range query(t, addr, name, qtype).Answer {
+			last = append(last, rr.String())
+		}
+		return fmt.Sprint(last) == fmt.Sprint(expected)
+	}, 5*time.Second, 20*time.Millisecond, "last answer: %v", last)
+}
+
+func TestResponder(t *testing.T) {
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	ctx := context.Background()
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "fd00::1"))
+	addr := startResponder(t, mgr)
+
+	// Existing and new entries.
+	eventuallyAnswers(t, addr, "pod-a.net1.global.l2sm", dns.TypeA, "pod-a.net1.global.l2sm.\t3600\tIN\tA\t10.0.0.1")
+	eventuallyAnswers(t, addr, "POD-A.net1.global.l2sm", dns.TypeAAAA, "pod-a.net1.global.l2sm.\t3600\tIN\tAAAA\tfd00::1")
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+	eventuallyAnswers(t, addr, "pod-b.net1.global.l2sm", dns.TypeA, "pod-b.net1.global.l2sm.\t3600\tIN\tA\t10.0.0.2")
+	eventuallyAnswers(t, addr, "2.0.0.10.in-addr.arpa", dns.TypePTR, "2.0.0.10.in-addr.arpa.\t3600\tIN\tPTR\tpod-b.net1.global.l2sm.")
+
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net1.global.l2sm"))
+	eventuallyAnswers(t, addr, "pod-b.net1.global.l2sm", dns.TypeA)
+	resp := query(t, addr, "pod-b.net1.global.l2sm", dns.TypeA)
+	require.Equal(t, dns.RcodeNameError, resp.Rcode)
+	require.True(t, resp.Authoritative)
+	require.Len(t, resp.Ns, 1)
+
+	// Names without records of the queried type, and empty non-terminals, exist.
+	resp = query(t, addr, "pod-a.net1.global.l2sm", dns.TypeTXT)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Empty(t, resp.Answer)
+	resp = query(t, addr, "net1.global.l2sm", dns.TypeA)
+	require.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	require.Empty(t, resp.Answer)
+
+	// CNAME, SRV and TXT records, with CNAMEs followed within the zone.
+	require.NoError(t, mgr.AddRecord(ctx, configmapmanager.Record{Name: "www.net1.global.l2sm", Type: configmapmanager.RecordTypeCNAME, TTL: 60, Target: "pod-a.net1.global.l2sm"}))
+	require.NoError(t, mgr.AddRecord(ctx, configmapmanager.Record{Name: "_http._tcp.web.net1.global.l2sm", Type: configmapmanager.RecordTypeSRV, TTL: 60, Priority: 10, Weight: 5, Port: 8080, Target: "pod-a.net1.global.l2sm"}))
+	eventuallyAnswers(t, addr, "www.net1.global.l2sm", dns.TypeA,
+		"www.net1.global.l2sm.\t60\tIN\tCNAME\tpod-a.net1.global.l2sm.",
+		"pod-a.net1.global.l2sm.\t3600\tIN\tA\t10.0.0.1",
+	)
+	eventuallyAnswers(t, addr, "_http._tcp.web.net1.global.l2sm", dns.TypeSRV,
+		"_http._tcp.web.net1.global.l2sm.\t60\tIN\tSRV\t10 5 8080 pod-a.net1.global.l2sm.")
+
+	// Without upstreams, other queries are refused.
+	require.Equal(t, dns.RcodeRefused, query(t, addr, "example.org", dns.TypeA).Rcode)
+}
+
+func TestResponderForwarding(t *testing.T) {
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	ctx := context.Background()
+	peer := startUpstream(t, "192.0.2.1")
+	fallback := startUpstream(t, "192.0.2.2")
+	host, port, err := net.SplitHostPort(peer)
+	require.NoError(t, err)
+	require.NoError(t, mgr.AddServerToConfigMap(ctx, "peer-a.org:53", host, port))
+
+	addr := startResponder(t, mgr, fallback)
+	eventuallyAnswers(t, addr, "pod.peer-a.org", dns.TypeA, "pod.peer-a.org.\t60\tIN\tA\t192.0.2.1")
+	eventuallyAnswers(t, addr, "example.org", dns.TypeA, "example.org.\t60\tIN\tA\t192.0.2.2")
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/coredns/caddy"
+	"github.com/coredns/coredns/core/dnsserver"
+	_ "github.com/coredns/coredns/plugin/bind"
+	_ "github.com/coredns/coredns/plugin/file"
+	_ "github.com/coredns/coredns/plugin/hosts"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// Reverse lookups
+// ----------------------------------------------
+func TestReverseZone(t *testing.T) {
+	tests := []struct {
+		ip       string
+		prefixV4 string
+		prefixV6 string
+		expected string
+	}{
+		{ip: "10.1.2.3", expected: "2.1.10.in-addr.arpa."},
+		{ip: "10.1.2.3", prefixV4: "16", expected: "1.10.in-addr.arpa."},
+		{ip: "10.1.2.3", prefixV4: "20", expected: "1.10.in-addr.arpa."},
+		{ip: "2001:db8::1", expected: "0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+		{ip: "2001:db8::1", prefixV6: "48", expected: "0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s/%s%s", tc.ip, tc.prefixV4, tc.prefixV6), func(t *testing.T) {
+			if tc.prefixV4 != "" {
+				t.Setenv("DNS_REVERSE_PREFIX_V4", tc.prefixV4)
+			}
+			if tc.prefixV6 != "" {
+				t.Setenv("DNS_REVERSE_PREFIX_V6", tc.prefixV6)
+			}
+			zone, err := configmapmanager.ReverseZone(tc.ip)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, zone)
+		})
+	}
+
+	_, err := configmapmanager.ReverseZone("not-an-ip")
+	require.Error(t, err)
+}
+
+// freePort returns a local port that is free for both UDP and TCP.
+func freePort(t *testing.T) int {
+	for i := 0; i < 10; i++ {
+		udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := udp.LocalAddr().(*net.UDPAddr).Port
+		tcp, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		udp.Close()
+		if err == nil {
+			tcp.Close()
+			return port
+		}
+	}
+	t.Fatal("could not find a free port")
+	return 0
+}
+
+// startCoreDNS serves the Corefile and data files of a ConfigMap with an in-process CoreDNS, and
+// returns its address.
+func startCoreDNS(t *testing.T, dir string, data map[string]string) string {
+	for key, value := range data {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, key), []byte(value), 0o644))
+	}
+	dnsserver.Quiet = true
+	instance, err := caddy.Start(caddy.CaddyfileInput{
+		Contents:       []byte(data["Corefile"]),
+		Filepath:       filepath.Join(dir, "Corefile"),
+		ServerTypeName: "dns",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = instance.Stop() })
+	return instance.Servers()[0].LocalAddr().String()
+}
+
+func query(t *testing.T, addr, name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	client := &dns.Client{Timeout: 2 * time.Second}
+	resp, _, err := client.Exchange(msg, addr)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestReverseLookupsEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	hostsFile := filepath.Join(dir, "hosts")
+	require.NoError(t, os.WriteFile(hostsFile, nil, 0o644))
+	interDomain := fmt.Sprintf(".:%d", freePort(t))
+	t.Setenv("INTER_DOMAIN_DOM_PORT", interDomain)
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+
+	// A hosts plugin restricted to the L2SM zone, without reverse lookups.
+	cm := createConfigMap("test-cm", "test-namespace", fmt.Sprintf(`%s {
+  bind 127.0.0.1
+  hosts %s l2sm {
+    no_reverse
+    fallthrough
+  }
+}`, interDomain, hostsFile))
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1", "2001:db8::1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1"))
+	require.NoError(t, mgr.AddRecord(ctx, configmapmanager.Record{
+		Name:   "web.net1.global.l2sm",
+		Type:   configmapmanager.RecordTypeCNAME,
+		Target: "pod-a.net1.global.l2sm",
+	}))
+
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data["Corefile"], "no_reverse")
+	require.Contains(t, cfg.Data["Corefile"], fmt.Sprintf("hosts %s l2sm 0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa. 0.0.10.in-addr.arpa. 1.0.10.in-addr.arpa. {", hostsFile))
+
+	addr := startCoreDNS(t, dir, cfg.Data)
+
+	ptrTests := []struct {
+		ip       string
+		expected string
+	}{
+		{ip: "10.0.0.1", expected: "pod-a.net1.global.l2sm."},
+		{ip: "10.0.1.1", expected: "pod-b.net2.global.l2sm."},
+		{ip: "2001:db8::1", expected: "pod-a.net1.global.l2sm."},
+	}
+	for _, tc := range ptrTests {
+		reverse, err := dns.ReverseAddr(tc.ip)
+		require.NoError(t, err)
+		resp := query(t, addr, reverse, dns.TypePTR)
+		require.Equal(t, dns.RcodeSuccess, resp.Rcode, tc.ip)
+		require.Len(t, resp.Answer, 1, tc.ip)
+		require.Equal(t, tc.expected, resp.Answer[0].(*dns.PTR).Ptr)
+	}
+
+	resp := query(t, addr, "pod-a.net1.global.l2sm", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "10.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	// CNAME records are served by the file plugin.
+	resp = query(t, addr, "web.net1.global.l2sm", dns.TypeCNAME)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "pod-a.net1.global.l2sm.", resp.Answer[0].(*dns.CNAME).Target)
+}
+
+func TestReverseZonesRemoved(t *testing.T) {
+	t.Setenv("COREDNS_CONFIG_DIR", t.TempDir())
+	// The operator serves 0.0.10.in-addr.arpa. itself.
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts /etc/hosts l2sm 0.0.10.in-addr.arpa {
+        fallthrough
+    }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+	hostsLine := func() string {
+		cfg, err := mgr.GetConfigMap(ctx)
+		require.NoError(t, err)
+		for _, line := range strings.Split(cfg.Data["Corefile"], "\n") {
+			if strings.Contains(line, "hosts ") {
+				return strings.TrimSpace(line)
+			}
+		}
+		t.Fatal("no hosts plugin in the Corefile")
+		return ""
+	}
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-c.net3.global.l2sm", "10.0.2.1"))
+	require.Equal(t, "hosts /etc/hosts l2sm 0.0.10.in-addr.arpa 1.0.10.in-addr.arpa. 2.0.10.in-addr.arpa. {", hostsLine())
+
+	// A zone the manager added is removed once no record needs it.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net2.global.l2sm"))
+	require.Equal(t, "hosts /etc/hosts l2sm 0.0.10.in-addr.arpa 2.0.10.in-addr.arpa. {", hostsLine())
+
+	// The zones of the Corefile are kept, even without records.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-c.net3.global.l2sm"))
+	require.Equal(t, "hosts /etc/hosts l2sm 0.0.10.in-addr.arpa {", hostsLine())
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data, configmapmanager.ReverseZonesKey)
+
+	// A removed zone is added back when needed again.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1"))
+	require.Equal(t, "hosts /etc/hosts l2sm 0.0.10.in-addr.arpa 1.0.10.in-addr.arpa. {", hostsLine())
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// RemoveServerFromConfigMap / ListServers
+// ----------------------------------------------
+func TestRemoveServerFromConfigMapEdgeCases(t *testing.T) {
+	validCorefile := `.:53 {
+  hosts {
+  }
+  forward . /etc/resolv.conf
+}
+peer.org:53 {
+  forward . 10.1.0.53:53
+}`
+
+	tests := []struct {
+		name           string
+		corefileData   string
+		domainName     string
+		expectErr      bool
+		expectedErrMsg string
+	}{
+		{
+			name:           "No 'Corefile' key in ConfigMap",
+			corefileData:   "",
+			domainName:     "peer.org:53",
+			expectErr:      true,
+			expectedErrMsg: "corefile not found in ConfigMap data",
+		},
+		{
+			name:           "Inter-domain server is protected",
+			corefileData:   validCorefile,
+			domainName:     ".:53",
+			expectErr:      true,
+			expectedErrMsg: "cannot be removed",
+		},
+		{
+			name:           "Unknown server",
+			corefileData:   validCorefile,
+			domainName:     "other.org:53",
+			expectErr:      true,
+			expectedErrMsg: "server other.org:53 not found in corefile",
+		},
+		{
+			name:         "Remove a server block successfully",
+			corefileData: validCorefile,
+			domainName:   "peer.org:53",
+			expectErr:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := createConfigMap("test-cm", "test-namespace", tc.corefileData)
+			mgr := newDNSManager(t, cm)
+
+			err := mgr.RemoveServerFromConfigMap(context.Background(), tc.domainName)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrMsg)
+			} else {
+				require.NoError(t, err)
+				servers, err := mgr.ListServers(context.Background())
+				require.NoError(t, err)
+				require.Empty(t, servers)
+			}
+		})
+	}
+}
+
+func TestListServers(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+  forward . /etc/resolv.conf
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	servers, err := mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, servers)
+
+	require.NoError(t, mgr.AddServerToConfigMap(ctx, "peer-a.org:53", "10.1.0.53", "53"))
+	require.NoError(t, mgr.AddServerToConfigMap(ctx, "peer-b.org:53", "dns.peer-b.org", "5353"))
+
+	servers, err = mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.ForwardServer{
+		{DomPort: "peer-a.org:53", ServerDomain: "10.1.0.53", ServerPort: "53", Forward: configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:53"}}},
+		{DomPort: "peer-b.org:53", ServerDomain: "dns.peer-b.org", ServerPort: "5353", Forward: configmapmanager.ForwardConfig{Upstreams: []string{"dns.peer-b.org:5353"}}},
+	}, servers)
+
+	require.NoError(t, mgr.RemoveServerFromConfigMap(ctx, "peer-a.org:53"))
+	servers, err = mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.ForwardServer{
+		{DomPort: "peer-b.org:53", ServerDomain: "dns.peer-b.org", ServerPort: "5353", Forward: configmapmanager.ForwardConfig{Upstreams: []string{"dns.peer-b.org:5353"}}},
+	}, servers)
+
+	// The entries of the inter-domain server are untouched.
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+// ----------------------------------------------
+// AddForwardServer
+// ----------------------------------------------
+func TestAddForwardServer(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	maxFails := 3
+	cfg := configmapmanager.ForwardConfig{
+		Upstreams:     []string{"10.1.0.53:853", "10.1.0.54:853"},
+		Policy:        configmapmanager.ForwardPolicyRoundRobin,
+		HealthCheck:   500 * time.Millisecond,
+		MaxFails:      &maxFails,
+		Expire:        10 * time.Second,
+		TLS:           true,
+		TLSServerName: "dns.peer.org",
+	}
+	result, err := mgr.AddForwardServer(ctx, "peer.org:53", cfg)
+	require.NoError(t, err)
+	require.True(t, result.Changed)
+
+	configMap, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Contains(t, configMap.Data["Corefile"], `peer.org:53 {
+    forward . tls://10.1.0.53:853 tls://10.1.0.54:853 {
+        policy round_robin
+        health_check 500ms
+        max_fails 3
+        expire 10s
+        tls_servername dns.peer.org
+    }
+}`)
+
+	servers, err := mgr.ListServers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []configmapmanager.ForwardServer{
+		{DomPort: "peer.org:53", ServerDomain: "10.1.0.53", ServerPort: "853", Forward: cfg},
+	}, servers)
+
+	// Adding the server again replaces its forward configuration.
+	result, err = mgr.AddForwardServer(ctx, "peer.org:53", configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.55:53"}})
+	require.NoError(t, err)
+	require.True(t, result.Changed)
+	configMap, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, configMap.ResourceVersion, result.ResourceVersion)
+	require.Contains(t, configMap.Data["Corefile"], `peer.org:53 {
+    forward . 10.1.0.55:53
+}`)
+}
+
+func TestForwardConfigValidate(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name           string
+		cfg            configmapmanager.ForwardConfig
+		expectedErrMsg string
+	}{
+		{
+			name:           "No upstreams",
+			cfg:            configmapmanager.ForwardConfig{},
+			expectedErrMsg: "at least one upstream server is required",
+		},
+		{
+			name:           "Upstream without port",
+			cfg:            configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53"}},
+			expectedErrMsg: "expected host:port",
+		},
+		{
+			name:           "Upstream with invalid port",
+			cfg:            configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:99999"}},
+			expectedErrMsg: "invalid port",
+		},
+		{
+			name:           "Unknown policy",
+			cfg:            configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:53"}, Policy: "fastest"},
+			expectedErrMsg: "invalid forward policy",
+		},
+		{
+			name:           "Negative max fails",
+			cfg:            configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:53"}, MaxFails: &negative},
+			expectedErrMsg: "max fails must not be negative",
+		},
+		{
+			name:           "TLS server name without TLS",
+			cfg:            configmapmanager.ForwardConfig{Upstreams: []string{"10.1.0.53:53"}, TLSServerName: "dns.peer.org"},
+			expectedErrMsg: "requires TLS",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErrMsg)
+		})
+	}
+}
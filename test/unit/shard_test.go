@@ -0,0 +1,307 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// ----------------------------------------------
+// Sharded record storage
+// ----------------------------------------------
+func newShardedDNSManager(t *testing.T, cm *corev1.ConfigMap) (configmapmanager.DNSManager, client.Client) {
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageSharded)
+	t.Setenv("DNS_RECORD_SHARDS", "2")
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	return mgr, fclient
+}
+
+// shardData returns the merged data of every shard ConfigMap.
+func shardData(t *testing.T, c client.Client) map[string]string {
+	list := &corev1.ConfigMapList{}
+	require.NoError(t, c.List(context.Background(), list, client.InNamespace("test-namespace")))
+	data := map[string]string{}
+	for _, cm := range list.Items {
+		if strings.HasPrefix(cm.Name, "test-cm-shard-") {
+			maps.Copy(data, cm.Data)
+		}
+	}
+	return data
+}
+
+func TestShardedStorage(t *testing.T) {
+	t.Setenv("COREDNS_CONFIG_DIR", "/etc/coredns")
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts {
+        10.0.0.1 pod-a.net1.global.l2sm
+    }
+}`)
+	mgr, c := newShardedDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net1.global.l2sm", "10.0.0.2"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-c.net2.global.l2sm", "10.0.1.1", "2001:db8::1"))
+	// Names directly under the TLD stay inline.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "gateway.l2sm", "10.0.2.1"))
+
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	corefile := cfg.Data["Corefile"]
+	require.NotContains(t, corefile, "pod-")
+	require.Contains(t, corefile, "10.0.2.1 gateway.l2sm")
+	require.Contains(t, corefile, "file /etc/coredns/db.net1.global.l2sm net1.global.l2sm")
+	require.Contains(t, corefile, "file /etc/coredns/db.net2.global.l2sm net2.global.l2sm")
+	require.Contains(t, corefile, "file /etc/coredns/db.0.0.10.in-addr.arpa 0.0.10.in-addr.arpa")
+	require.Contains(t, corefile, "fallthrough")
+	require.Contains(t, cfg.Data, configmapmanager.ShardsKey)
+
+	shards := shardData(t, c)
+	require.Contains(t, shards["db.net1.global.l2sm"], "pod-a.net1.global.l2sm.\t3600\tIN\tA\t10.0.0.1")
+	require.Contains(t, shards["db.net1.global.l2sm"], "pod-b.net1.global.l2sm.\t3600\tIN\tA\t10.0.0.2")
+	require.Contains(t, shards["db.net2.global.l2sm"], "pod-c.net2.global.l2sm.\t3600\tIN\tAAAA\t2001:db8::1")
+	require.Contains(t, shards["db.1.0.10.in-addr.arpa"], "PTR\tpod-c.net2.global.l2sm.")
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1":    {"pod-a.net1.global.l2sm"},
+		"10.0.0.2":    {"pod-b.net1.global.l2sm"},
+		"10.0.1.1":    {"pod-c.net2.global.l2sm"},
+		"2001:db8::1": {"pod-c.net2.global.l2sm"},
+		"10.0.2.1":    {"gateway.l2sm"},
+	}, records)
+
+	// Removing the last name of a zone drops its zone file.
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-c.net2.global.l2sm"))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data["Corefile"], "net2.global.l2sm")
+	shards = shardData(t, c)
+	require.NotContains(t, shards, "db.net2.global.l2sm")
+	require.NotContains(t, shards, "db.1.0.10.in-addr.arpa")
+	require.Contains(t, shards, "db.net1.global.l2sm")
+
+	// A no-op write leaves everything untouched.
+	rv := cfg.ResourceVersion
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rv, cfg.ResourceVersion)
+
+	// Back to inline storage.
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageInline)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data, configmapmanager.ShardsKey)
+	require.NotContains(t, cfg.Data["Corefile"], "file /etc/coredns/db.")
+	require.Contains(t, cfg.Data["Corefile"], "10.0.0.1 pod-a.net1.global.l2sm")
+	require.Empty(t, shardData(t, c))
+}
+
+func TestShardedStorageCopiesZoneRecords(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts {
+    }
+}`)
+	mgr, c := newShardedDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddRecord(ctx, configmapmanager.Record{
+		Name:   "web.net1.global.l2sm",
+		Type:   configmapmanager.RecordTypeCNAME,
+		Target: "pod-a.net1.global.l2sm",
+	}))
+	require.Contains(t, shardData(t, c)["db.net1.global.l2sm"], "web.net1.global.l2sm.\t3600\tIN\tCNAME\tpod-a.net1.global.l2sm.")
+
+	require.NoError(t, mgr.RemoveRecord(ctx, configmapmanager.Record{
+		Name:   "web.net1.global.l2sm",
+		Type:   configmapmanager.RecordTypeCNAME,
+		Target: "pod-a.net1.global.l2sm",
+	}))
+	require.NotContains(t, shardData(t, c)["db.net1.global.l2sm"], "CNAME")
+}
+
+func TestShardedStorageConflictRetry(t *testing.T) {
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageSharded)
+	t.Setenv("DNS_RECORD_SHARDS", "2")
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts {
+        10.0.0.1 pod-a.net1.global.l2sm
+    }
+}`)
+	// The updates of the ConfigMap fail with a conflict as long as conflicts is positive.
+	var conflicts int32
+	c := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if obj.GetName() == "test-cm" && atomic.AddInt32(&conflicts, -1) >= 0 {
+					return apierrors.NewConflict(corev1.Resource("configmaps"), obj.GetName(), errors.New("modified"))
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, c)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	shards := shardData(t, c)
+	require.Contains(t, shards, "db.net1.global.l2sm")
+
+	// A write that keeps conflicting leaves the shards as they were.
+	atomic.StoreInt32(&conflicts, 100)
+	err = mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1")
+	require.Equal(t, configmapmanager.ErrConflict, configmapmanager.ErrorKind(err))
+	require.Equal(t, shards, shardData(t, c))
+	err = mgr.AddDNSEntry(ctx, "pod-c.net1.global.l2sm", "10.0.0.3")
+	require.Equal(t, configmapmanager.ErrConflict, configmapmanager.ErrorKind(err))
+	require.Equal(t, shards, shardData(t, c))
+
+	// A write that conflicts once is retried, and the shards hold exactly the zone files it references.
+	atomic.StoreInt32(&conflicts, 1)
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1"))
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.1.1": {"pod-b.net2.global.l2sm"},
+	}, records)
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	var keys []string
+	for key := range shardData(t, c) {
+		keys = append(keys, key)
+		require.Contains(t, cfg.Data["Corefile"], "/"+key+" ")
+	}
+	require.ElementsMatch(t, []string{"db.net1.global.l2sm", "db.net2.global.l2sm", "db.0.0.10.in-addr.arpa", "db.1.0.10.in-addr.arpa"}, keys)
+	require.NotContains(t, shardData(t, c)["db.net1.global.l2sm"], "pod-c")
+}
+
+func TestConfigMapSizeLimit(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts {
+    }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+	t.Setenv("DNS_CONFIGMAP_MAX_SIZE", "200")
+
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = mgr.AddDNSEntry(ctx, fmt.Sprintf("pod-%d.net1.global.l2sm", i), fmt.Sprintf("10.0.0.%d", i+1))
+	}
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "over the 200 bytes limit")
+
+	// The failed write left the ConfigMap within the limit.
+	cfg, getErr := mgr.GetConfigMap(ctx)
+	require.NoError(t, getErr)
+	require.LessOrEqual(t, len(cfg.Data["Corefile"]), 200)
+}
+
+func TestShardedStorageEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	interDomain := fmt.Sprintf(".:%d", freePort(t))
+	t.Setenv("INTER_DOMAIN_DOM_PORT", interDomain)
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+
+	cm := createConfigMap("test-cm", "test-namespace", fmt.Sprintf(`%s {
+  bind 127.0.0.1
+  hosts {
+  }
+}`, interDomain))
+	mgr, c := newShardedDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-b.net2.global.l2sm", "10.0.1.1"))
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+
+	// The CoreDNS container mounts the ConfigMap and its shards on the same directory.
+	data := shardData(t, c)
+	maps.Copy(data, cfg.Data)
+	addr := startCoreDNS(t, dir, data)
+
+	resp := query(t, addr, "pod-a.net1.global.l2sm", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "10.0.0.1", resp.Answer[0].(*dns.A).A.String())
+	resp = query(t, addr, "pod-b.net2.global.l2sm", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "10.0.1.1", resp.Answer[0].(*dns.A).A.String())
+
+	reverse, err := dns.ReverseAddr("10.0.1.1")
+	require.NoError(t, err)
+	resp = query(t, addr, reverse, dns.TypePTR)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "pod-b.net2.global.l2sm.", resp.Answer[0].(*dns.PTR).Ptr)
+}
+
+func TestCheckShardVolumes(t *testing.T) {
+	t.Setenv("DNS_RECORD_SHARDS", "2")
+	projected := func(names ...string) *corev1.Pod {
+		var sources []corev1.VolumeProjection
+		for _, name := range names {
+			sources = append(sources, corev1.VolumeProjection{ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			}})
+		}
+		return &corev1.Pod{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name:         "config-volume",
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: sources}},
+		}}}}
+	}
+
+	mounted, err := configmapmanager.CheckShardVolumes(projected("test-cm", "test-cm-shard-0", "test-cm-shard-1"), "test-cm")
+	require.NoError(t, err)
+	require.True(t, mounted)
+
+	// A shard is missing, e.g. DNS_RECORD_SHARDS was raised without editing the manifest.
+	_, err = configmapmanager.CheckShardVolumes(projected("test-cm", "test-cm-shard-0"), "test-cm")
+	require.ErrorContains(t, err, "test-cm-shard-1")
+
+	// The shards keep the names they had before a kustomize namePrefix.
+	_, err = configmapmanager.CheckShardVolumes(projected("prefix-test-cm", "test-cm-shard-0", "test-cm-shard-1"), "prefix-test-cm")
+	require.ErrorContains(t, err, "prefix-test-cm-shard-0")
+
+	// CoreDNS runs in another pod.
+	mounted, err = configmapmanager.CheckShardVolumes(projected("other"), "test-cm")
+	require.NoError(t, err)
+	require.False(t, mounted)
+}
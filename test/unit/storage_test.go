@@ -0,0 +1,173 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// Record storage
+// ----------------------------------------------
+func TestMigrateRecordStorage(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+	hostsFile := filepath.Join(dir, configmapmanager.HostsKey)
+
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts {
+        10.0.0.1 pod-a.net1.global.l2sm
+        10.0.0.2 pod-b.net1.global.l2sm
+        fallthrough
+    }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+	expected := map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.2": {"pod-b.net1.global.l2sm"},
+	}
+
+	// Inline to hosts file.
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageHostsFile)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Contains(t, cfg.Data["Corefile"], fmt.Sprintf("hosts %s {", hostsFile))
+	require.NotContains(t, cfg.Data["Corefile"], "10.0.0.1")
+	require.Contains(t, cfg.Data["Corefile"], "fallthrough")
+	require.Contains(t, cfg.Data[configmapmanager.HostsKey], "10.0.0.1 pod-a.net1.global.l2sm\n")
+	require.Contains(t, cfg.Data[configmapmanager.HostsKey], "10.0.0.2 pod-b.net1.global.l2sm\n")
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, records)
+
+	// Entry changes only touch the hosts file.
+	corefileBefore := cfg.Data["Corefile"]
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-c.net1.global.l2sm", "10.0.0.3"))
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-b.net1.global.l2sm"))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, corefileBefore, cfg.Data["Corefile"])
+	require.Contains(t, cfg.Data[configmapmanager.HostsKey], "10.0.0.3 pod-c.net1.global.l2sm\n")
+	require.NotContains(t, cfg.Data[configmapmanager.HostsKey], "pod-b")
+
+	// Migrating again is a no-op.
+	rv := cfg.ResourceVersion
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rv, cfg.ResourceVersion)
+
+	// Hosts file back to inline.
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageInline)
+	require.NoError(t, mgr.MigrateRecordStorage(ctx))
+	cfg, err = mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data, configmapmanager.HostsKey)
+	require.NotContains(t, cfg.Data["Corefile"], hostsFile)
+	require.Contains(t, cfg.Data["Corefile"], "10.0.0.3 pod-c.net1.global.l2sm")
+
+	records, err = mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"pod-a.net1.global.l2sm"},
+		"10.0.0.3": {"pod-c.net1.global.l2sm"},
+	}, records)
+}
+
+func TestHostsFileRecordChanges(t *testing.T) {
+	t.Setenv("COREDNS_CONFIG_DIR", t.TempDir())
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageHostsFile)
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+    hosts /etc/hosts global.l2sm {
+        fallthrough
+    }
+}`)
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+	corefile := func() string {
+		cfg, err := mgr.GetConfigMap(ctx)
+		require.NoError(t, err)
+		return cfg.Data["Corefile"]
+	}
+
+	// The first address of a subnet adds its reverse zone to the Corefile.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	require.Contains(t, corefile(), "global.l2sm 0.0.10.in-addr.arpa.")
+
+	// Other records of the subnet, and their leases, leave the Corefile as it is.
+	before := corefile()
+	require.NoError(t, mgr.AddDNSEntryWithLease(ctx, "pod-b.net1.global.l2sm", time.Minute, "10.0.0.2"))
+	require.NoError(t, mgr.UpdateDNSRecords(ctx, map[string][]string{"10.0.0.3": {"pod-c.net1.global.l2sm"}}, nil))
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "pod-a.net1.global.l2sm"))
+	removed, err := mgr.ExpireDNSLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.2": {"pod-b.net1.global.l2sm"}}, removed)
+	require.Equal(t, before, corefile())
+
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, cfg.Data, configmapmanager.LeasesKey)
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.3": {"pod-c.net1.global.l2sm"}}, records)
+
+	// An address of another subnet goes through the Corefile again.
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-d.net2.global.l2sm", "10.0.1.1"))
+	require.Contains(t, corefile(), "0.0.10.in-addr.arpa. 1.0.10.in-addr.arpa.")
+}
+
+func TestHostsFileStorageEndToEnd(t *testing.T) {
+	dir := t.TempDir()
+	interDomain := fmt.Sprintf(".:%d", freePort(t))
+	t.Setenv("INTER_DOMAIN_DOM_PORT", interDomain)
+	t.Setenv("COREDNS_CONFIG_DIR", dir)
+	t.Setenv("DNS_RECORD_STORAGE", configmapmanager.StorageHostsFile)
+
+	cm := createConfigMap("test-cm", "test-namespace", fmt.Sprintf(`%s {
+  bind 127.0.0.1
+  hosts {
+    fallthrough
+  }
+}`, interDomain))
+	mgr := newDNSManager(t, cm)
+	ctx := context.Background()
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	cfg, err := mgr.GetConfigMap(ctx)
+	require.NoError(t, err)
+
+	addr := startCoreDNS(t, dir, cfg.Data)
+
+	resp := query(t, addr, "pod-a.net1.global.l2sm", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "10.0.0.1", resp.Answer[0].(*dns.A).A.String())
+
+	reverse, err := dns.ReverseAddr("10.0.0.1")
+	require.NoError(t, err)
+	resp = query(t, addr, reverse, dns.TypePTR)
+	require.Len(t, resp.Answer, 1)
+	require.Equal(t, "pod-a.net1.global.l2sm.", resp.Answer[0].(*dns.PTR).Ptr)
+}
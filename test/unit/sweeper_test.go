@@ -0,0 +1,216 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/internal/controller"
+	configmapmanager "github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// ----------------------------------------------
+// Stale entry sweeper
+// ----------------------------------------------
+
+// sweeperNames returns a naming template that tells apart the pods of different namespaces and
+// clusters, as the sweeper requires.
+func sweeperNames(t *testing.T) *configmapmanager.NameTemplate {
+	t.Helper()
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Namespace}}.{{.Network}}.{{.Cluster}}", "l2sm")
+	require.NoError(t, err)
+	return names
+}
+
+func TestSweeper(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 alive.default.net1.c1.l2sm
+    10.0.0.2 dead.default.net1.c1.l2sm
+    10.0.0.3 finished.default.net1.c1.l2sm
+    10.0.0.4 static-name
+    fallthrough
+  }
+}`)
+	alive := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "alive", Namespace: "default"}}
+	finished := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "finished", Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm, alive, finished).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+	stale := map[string][]string{
+		"10.0.0.2": {"dead.default.net1.c1.l2sm"},
+		"10.0.0.3": {"finished.default.net1.c1.l2sm"},
+	}
+
+	t.Run("dry run keeps entries", func(t *testing.T) {
+		sweeper := &controller.Sweeper{Client: fclient, DNSManager: mgr, Cluster: "c1", Names: sweeperNames(t), DryRun: true}
+		removed, err := sweeper.Sweep(ctx)
+		require.NoError(t, err)
+		require.Equal(t, stale, removed)
+
+		records, err := mgr.ListDNSRecords(ctx)
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		sweeper := &controller.Sweeper{Client: fclient, DNSManager: mgr, Cluster: "c1", Names: sweeperNames(t), GracePeriod: 50 * time.Millisecond}
+		removed, err := sweeper.Sweep(ctx)
+		require.NoError(t, err)
+		require.Empty(t, removed)
+
+		time.Sleep(60 * time.Millisecond)
+		removed, err = sweeper.Sweep(ctx)
+		require.NoError(t, err)
+		require.Equal(t, stale, removed)
+
+		records, err := mgr.ListDNSRecords(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{
+			"10.0.0.1": {"alive.default.net1.c1.l2sm"},
+			"10.0.0.4": {"static-name"},
+		}, records)
+	})
+}
+
+func TestSweeperForgetsRecoveredEntries(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 late.default.net1.c1.l2sm
+  }
+}`)
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	sweeper := &controller.Sweeper{Client: fclient, DNSManager: mgr, Cluster: "c1", Names: sweeperNames(t), GracePeriod: 50 * time.Millisecond}
+	removed, err := sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	// The pod shows up after its entry was registered: the entry is no longer an orphan.
+	require.NoError(t, fclient.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "late", Namespace: "default"}}))
+	time.Sleep(60 * time.Millisecond)
+	removed, err = sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"late.default.net1.c1.l2sm"}}, records)
+}
+
+func TestSweeperOwnership(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 web.ns-a.net1.c1.l2sm
+    10.0.0.2 web.ns-b.net1.c1.l2sm
+    10.0.0.3 web.ns-a.net1.c2.l2sm
+    10.0.0.4 db.ns-a.net1.c1.l2sm
+    10.0.0.5 db.ns-a.net1.c1.l2sm
+    10.0.0.6 pending.ns-a.net1.c1.l2sm
+  }
+}`)
+	pods := []*corev1.Pod{
+		// ns-a/web is running on 10.0.0.1, but there is no ns-b/web.
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns-a"},
+			Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}}},
+		},
+		// ns-a/db only kept 10.0.0.4, on its L2SM network; 10.0.0.5 may now be another pod's.
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "db",
+				Namespace:   "ns-a",
+				Annotations: map[string]string{controller.NetworkStatusAnnotation: `[{"name": "ns-a/net1", "ips": ["10.0.0.4"]}]`},
+			},
+			Status: corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.244.0.7"}}},
+		},
+		// ns-a/pending has no address yet.
+		{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "ns-a"}},
+	}
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm, pods[0], pods[1], pods[2]).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The entry of the peer cluster c2 is never swept, even if no local pod matches it.
+	sweeper := &controller.Sweeper{Client: fclient, DNSManager: mgr, Cluster: "c1", Names: sweeperNames(t)}
+	removed, err := sweeper.Sweep(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.2": {"web.ns-b.net1.c1.l2sm"},
+		"10.0.0.5": {"db.ns-a.net1.c1.l2sm"},
+	}, removed)
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"10.0.0.1": {"web.ns-a.net1.c1.l2sm"},
+		"10.0.0.3": {"web.ns-a.net1.c2.l2sm"},
+		"10.0.0.4": {"db.ns-a.net1.c1.l2sm"},
+		"10.0.0.6": {"pending.ns-a.net1.c1.l2sm"},
+	}, records)
+}
+
+func TestSweeperNeedsNamespaceAndCluster(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    10.0.0.1 dead.net1.global.l2sm
+  }
+}`)
+	fclient := crfake.NewClientBuilder().
+		WithScheme(createFakeScheme()).
+		WithObjects(cm).
+		Build()
+	mgr, err := configmapmanager.NewDNSManager("test-namespace", "test-cm", nil, fclient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	names, err := configmapmanager.NewNameTemplate("{{.PodName}}.{{.Network}}.{{.Scope}}", "l2sm")
+	require.NoError(t, err)
+	sweeper := &controller.Sweeper{Client: fclient, DNSManager: mgr, Cluster: "c1", Names: names}
+	_, err = sweeper.Sweep(ctx)
+	require.ErrorContains(t, err, "{{.Namespace}}")
+	require.Error(t, sweeper.Start(ctx))
+
+	sweeper = &controller.Sweeper{Client: fclient, DNSManager: mgr, Names: sweeperNames(t)}
+	_, err = sweeper.Sweep(ctx)
+	require.Error(t, err)
+
+	records, err := mgr.ListDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"10.0.0.1": {"dead.net1.global.l2sm"}}, records)
+}
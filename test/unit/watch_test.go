@@ -0,0 +1,110 @@
+// Copyright 2025 Alejandro de Cock Buning; Ivan Vidal; Francisco Valera; Diego R. Lopez.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmapmanager_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Networks-it-uc3m/l2sm-dns/pkg/configmapmanager"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------
+// WatchDNSRecords
+// ----------------------------------------------
+func nextEvent(t *testing.T, events <-chan configmapmanager.RecordEvent) configmapmanager.RecordEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "event channel closed")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a record event")
+		return configmapmanager.RecordEvent{}
+	}
+}
+
+func TestWatchDNSRecords(t *testing.T) {
+	cm := createConfigMap("test-cm", "test-namespace", `.:53 {
+  hosts {
+    1.2.3.4 existing.net1.global.l2sm
+  }
+}`)
+	mgr := newDNSManager(t, cm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	records, resourceVersion, events, err := mgr.WatchDNSRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"1.2.3.4": {"existing.net1.global.l2sm"}}, records)
+	require.NotEmpty(t, resourceVersion)
+
+	require.NoError(t, mgr.AddDNSEntry(ctx, "pod-a.net1.global.l2sm", "10.0.0.1"))
+	event := nextEvent(t, events)
+	require.Equal(t, configmapmanager.RecordAdded, event.Type)
+	require.Equal(t, "pod-a.net1.global.l2sm", event.DNSName)
+	require.Equal(t, "10.0.0.1", event.IPAddress)
+	require.NotEqual(t, resourceVersion, event.ResourceVersion)
+
+	require.NoError(t, mgr.RemoveDNSEntry(ctx, "existing.net1.global.l2sm", "1.2.3.4"))
+	event = nextEvent(t, events)
+	require.Equal(t, configmapmanager.RecordDeleted, event.Type)
+	require.Equal(t, "existing.net1.global.l2sm", event.DNSName)
+	require.Equal(t, "1.2.3.4", event.IPAddress)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchDNSRecordsStalledWatcher(t *testing.T) {
+	mgr := configmapmanager.NewMemoryDNSManager("")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A watcher that does not read its events does not hold up the writers.
+	_, _, events, err := mgr.WatchDNSRecords(ctx)
+	require.NoError(t, err)
+	const writes = 300
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < writes; i++ {
+			if err := mgr.AddDNSEntry(ctx, fmt.Sprintf("pod-%d.net1.global.l2sm", i), fmt.Sprintf("10.0.%d.%d", i/256, i%256)); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("writes blocked on a watcher that does not read")
+	}
+
+	// Once it reads again, it catches up with every write.
+	last := fmt.Sprintf("pod-%d.net1.global.l2sm", writes-1)
+	for {
+		event := nextEvent(t, events)
+		if event.Type == configmapmanager.RecordAdded && event.DNSName == last {
+			break
+		}
+	}
+}